@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/game"
+)
+
+// premiumMarks are shown on empty premium squares
+var premiumMarks = map[board.MultiplierType]string{
+	board.DoubleLetter:    "'",
+	board.TripleLetter:    "\"",
+	board.QuadrupleLetter: "^",
+	board.DoubleWord:      "-",
+	board.TripleWord:      "=",
+	board.QuadrupleWord:   "~",
+}
+
+// renderBoard draws the board labelled as moves are written, columns from A
+// and rows from 1. Blanks are lower case and empty premium squares are marked.
+func renderBoard(w io.Writer, b *board.Board, alphabet *game.Alphabet) {
+	size := b.Size()
+	center := b.Center()
+
+	fmt.Fprint(w, "    ")
+	for col := 0; col < size; col++ {
+		fmt.Fprintf(w, " %c", 'A'+col)
+	}
+	fmt.Fprintln(w)
+	border := "    " + strings.Repeat("--", size) + "-"
+	fmt.Fprintln(w, border)
+
+	for row := 0; row < size; row++ {
+		fmt.Fprintf(w, "%3d|", row+1)
+		for col := 0; col < size; col++ {
+			fmt.Fprint(w, " "+squareMark(b, row, col, center, alphabet))
+		}
+		fmt.Fprintln(w, " |")
+	}
+
+	fmt.Fprintln(w, border)
+	fmt.Fprintf(w, "     %s* centre  lower case: blank\n", premiumLegend(b))
+}
+
+// premiumLegend explains the marks of the premium squares the board has,
+// word premiums first and the largest first
+func premiumLegend(b *board.Board) string {
+	found := make(map[board.MultiplierType]board.Multiplier)
+	for row := 0; row < b.Size(); row++ {
+		for col := 0; col < b.Size(); col++ {
+			if m := b.GetMultiplier(row, col); premiumMarks[m.Type] != "" {
+				found[m.Type] = m
+			}
+		}
+	}
+
+	premiums := make([]board.Multiplier, 0, len(found))
+	for _, m := range found {
+		premiums = append(premiums, m)
+	}
+	sort.Slice(premiums, func(i, j int) bool {
+		if premiums[i].IsWord() != premiums[j].IsWord() {
+			return premiums[i].IsWord()
+		}
+		return premiums[i].Value > premiums[j].Value
+	})
+
+	var sb strings.Builder
+	for _, m := range premiums {
+		kind := "L"
+		if m.IsWord() {
+			kind = "W"
+		}
+		fmt.Fprintf(&sb, "%s %d%s  ", premiumMarks[m.Type], m.Value, kind)
+	}
+	return sb.String()
+}
+
+// squareMark returns what a square shows: its tile, premium or a dot
+func squareMark(b *board.Board, row, col int, center game.Position, alphabet *game.Alphabet) string {
+	if tile := b.GetTile(row, col); tile != nil {
+		symbol := alphabet.Display(tile.Letter)
+		if tile.IsBlank {
+			return strings.ToLower(symbol)
+		}
+		return symbol
+	}
+	if row == center.Row && col == center.Col {
+		return "*"
+	}
+	if mark, ok := premiumMarks[b.GetMultiplier(row, col).Type]; ok {
+		return mark
+	}
+	return "."
+}
+
+// rackString spells tiles as the alphabet displays them, with '?' for blanks
+func rackString(tiles []game.Tile, alphabet *game.Alphabet) string {
+	var sb strings.Builder
+	for _, tile := range tiles {
+		if tile.IsBlank {
+			sb.WriteRune(game.BlankLetter)
+		} else {
+			sb.WriteString(alphabet.Display(tile.Letter))
+		}
+	}
+	return sb.String()
+}
+
+// unseenString spells the unseen tiles in distribution order, blanks last
+func unseenString(unseen map[rune]int, dist *game.LetterDistribution) string {
+	var sb strings.Builder
+	for _, letter := range append(append([]rune(nil), dist.Letters...), game.BlankLetter) {
+		symbol := string(game.BlankLetter)
+		if letter != game.BlankLetter {
+			symbol = dist.Alphabet().Display(letter)
+		}
+		sb.WriteString(strings.Repeat(symbol, unseen[letter]))
+	}
+	return sb.String()
+}
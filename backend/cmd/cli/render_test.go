@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+	"tiletactics/backend/internal/board"
+)
+
+func TestPremiumLegend(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout *board.Layout
+		want   string
+	}{
+		{"standard", board.StandardLayout(), `= 3W  - 2W  " 3L  ' 2L  `},
+		{"super", board.SuperLayout(), `~ 4W  = 3W  - 2W  ^ 4L  " 3L  ' 2L  `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := premiumLegend(board.NewWithLayout(tt.layout)); got != tt.want {
+				t.Errorf("premiumLegend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
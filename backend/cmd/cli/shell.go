@@ -0,0 +1,647 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/cgp"
+	"tiletactics/backend/internal/evaluator"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/gcg"
+	"tiletactics/backend/internal/generator"
+	"tiletactics/backend/internal/simulator"
+	"tiletactics/backend/internal/validator"
+)
+
+const (
+	defaultListed     = 10  // Moves listed by gen and eval when no count is given
+	defaultCandidates = 5   // Candidates simulated when no count is given
+	defaultIterations = 200 // Playouts per candidate when no count is given
+)
+
+// errQuit is returned by the quit command to end the session
+var errQuit = errors.New("quit")
+
+// command is one shell command
+type command struct {
+	name  string
+	args  string
+	help  string
+	run   func(s *shell, args []string) error
+	shows bool // Print the board after the command succeeds
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"lexicon", "NAME|FILE", "load a word list or .gaddag file, by name from the dictionary directory or by path", (*shell).lexiconCommand, false},
+		{"leaves", "FILE|off", "value leaves with a table written by leavegen, or go back to the hand-tuned values", (*shell).leavesCommand, false},
+		{"rack", "TILES", "set the rack, ? for a blank", (*shell).rackCommand, false},
+		{"set", "COORD TILES", "put tiles on the board from a square, across for 8H and down for H8; lower case for blanks", (*shell).setCommand, true},
+		{"clear", "[COORD [N]]", "empty N squares from COORD, or the whole board", (*shell).clearCommand, true},
+		{"gen", "[N]", "list the N highest-scoring moves", (*shell).genCommand, false},
+		{"eval", "[N]", "list the N best moves by static evaluation", (*shell).evalCommand, false},
+		{"sim", "[N [ITERATIONS]]", "simulate the N best static moves", (*shell).simCommand, false},
+		{"play", "MOVE|N", "play a move such as 8H Q(U)IZ, -AB or -, or the Nth move listed, and pass the turn", (*shell).playCommand, true},
+		{"undo", "", "take back the last change to the position", (*shell).undoCommand, true},
+		{"show", "", "print the board, rack and unseen tiles", (*shell).showCommand, false},
+		{"load", "FILE.gcg", "load the final position of a game record", (*shell).loadCommand, true},
+		{"cgp", "[POSITION]", "print the position as a CGP string, or set it from one", (*shell).cgpCommand, false},
+		{"help", "", "list commands", (*shell).helpCommand, false},
+		{"quit", "", "leave the shell", func(*shell, []string) error { return errQuit }, false},
+	}
+}
+
+// shell is the state of an analysis session
+type shell struct {
+	out     io.Writer
+	dist    *game.LetterDistribution
+	dictDir string
+	workers int
+	replies int
+
+	lexicon gaddag.Lexicon
+	leaves  *evaluator.LeaveTable // Used by eval and sim; nil for the hand-tuned values
+	pos     *cgp.Position
+	history []snapshot  // States before each change, for undo
+	listed  []game.Move // Moves last listed by gen, eval or sim, for play N
+}
+
+// snapshot is the position before a change
+type snapshot struct {
+	board     *board.Board
+	racks     [2][]game.Tile
+	scores    [2]int
+	zeroTurns int
+}
+
+func newShell(out io.Writer, dictDir string, workers, replies int) *shell {
+	dist := game.EnglishDistribution()
+	return &shell{
+		out:     out,
+		dist:    dist,
+		dictDir: dictDir,
+		workers: workers,
+		replies: replies,
+		pos:     cgp.NewWithDistribution(board.New(), nil, dist),
+	}
+}
+
+// run executes one line of input
+func (s *shell) run(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	name := strings.ToLower(fields[0])
+	if name == "exit" {
+		name = "quit"
+	}
+	for _, c := range commands {
+		if c.name == name {
+			if err := c.run(s, fields[1:]); err != nil {
+				return err
+			}
+			if c.shows {
+				s.show()
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown command %q; try help", fields[0])
+}
+
+func (s *shell) helpCommand(args []string) error {
+	for _, c := range commands {
+		fmt.Fprintf(s.out, "  %-28s %s\n", strings.TrimSpace(c.name+" "+c.args), c.help)
+	}
+	return nil
+}
+
+// lexiconCommand loads a lexicon by path, or by name from the dictionary
+// directory, preferring a compiled .gaddag file to the word list
+func (s *shell) lexiconCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: lexicon NAME|FILE")
+	}
+	return s.loadLexicon(args[0])
+}
+
+func (s *shell) loadLexicon(name string) error {
+	lexicon, loaded, err := openLexicon(name, s.dictDir, s.dist.Alphabet())
+	if err != nil {
+		return err
+	}
+
+	s.lexicon = lexicon
+	s.pos.Lexicon = loaded
+	fmt.Fprintf(s.out, "Loaded %s\n", s.pos.Lexicon)
+	return nil
+}
+
+// leavesCommand sets the leave table eval and sim value leaves with
+func (s *shell) leavesCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: leaves FILE|off")
+	}
+	if strings.EqualFold(args[0], "off") {
+		s.leaves = nil
+		fmt.Fprintln(s.out, "Using hand-tuned leave values")
+		return nil
+	}
+
+	leaves, err := evaluator.LoadLeaves(args[0])
+	if err != nil {
+		return err
+	}
+	s.leaves = leaves
+	fmt.Fprintf(s.out, "Loaded %d leaves\n", leaves.Len())
+	return nil
+}
+
+// openLexicon loads a word list or .gaddag file, by name from dictDir or by
+// path, and returns it with the name it was found under
+func openLexicon(name, dictDir string, alphabet *game.Alphabet) (gaddag.Lexicon, string, error) {
+	filename := name
+	if _, err := os.Stat(filename); err != nil {
+		filename = filepath.Join(dictDir, name+".gaddag")
+		if _, err := os.Stat(filename); err != nil {
+			filename = filepath.Join(dictDir, name+".txt")
+		}
+	}
+
+	var lexicon gaddag.Lexicon
+	var err error
+	if filepath.Ext(filename) == ".gaddag" {
+		lexicon, err = gaddag.LoadCompact(filename)
+	} else {
+		var g *gaddag.GADDAG
+		if g, err = gaddag.LoadWithAlphabet(filename, alphabet); err == nil {
+			g.Minimize()
+			lexicon = g.Compact()
+		}
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load lexicon %s: %w", name, err)
+	}
+	return lexicon, strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)), nil
+}
+
+func (s *shell) rackCommand(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: rack TILES")
+	}
+
+	var rack []game.Tile
+	if len(args) == 1 {
+		var err error
+		if rack, err = parseRack(args[0], s.dist); err != nil {
+			return err
+		}
+	}
+
+	s.save()
+	s.pos.Racks[0] = rack
+	if _, err := s.pos.Unseen(); err != nil {
+		s.restore()
+		return err
+	}
+	s.listed = nil
+	return nil
+}
+
+func (s *shell) setCommand(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: set COORD TILES")
+	}
+	move, err := game.ParseMove(args[0]+" "+args[1], s.dist)
+	if err != nil {
+		return err
+	}
+	for _, placed := range move.TilesPlaced {
+		if !s.pos.Board.InBounds(placed.Position.Row, placed.Position.Col) {
+			return fmt.Errorf("%s %s runs off the board", args[0], args[1])
+		}
+	}
+
+	s.save()
+	s.pos.Board.Place(move)
+	if _, err := s.pos.Unseen(); err != nil {
+		s.restore()
+		return err
+	}
+	s.listed = nil
+	return nil
+}
+
+func (s *shell) clearCommand(args []string) error {
+	if len(args) == 0 {
+		s.save()
+		s.pos.Board = board.NewWithLayout(s.pos.Board.Layout())
+		s.listed = nil
+		return nil
+	}
+	if len(args) > 2 {
+		return fmt.Errorf("usage: clear [COORD [N]]")
+	}
+
+	pos, dir, err := game.ParseCoordinate(args[0])
+	if err != nil {
+		return err
+	}
+	n := 1
+	if len(args) == 2 {
+		if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+			return fmt.Errorf("bad square count %q", args[1])
+		}
+	}
+
+	s.save()
+	for i := 0; i < n; i++ {
+		if s.pos.Board.InBounds(pos.Row, pos.Col) {
+			s.pos.Board.SetTile(pos.Row, pos.Col, nil)
+		}
+		if dir == game.Horizontal {
+			pos.Col++
+		} else {
+			pos.Row++
+		}
+	}
+	s.listed = nil
+	return nil
+}
+
+func (s *shell) genCommand(args []string) error {
+	n, err := countArg(args, 0, defaultListed)
+	if err != nil {
+		return err
+	}
+	if err := s.ready(); err != nil {
+		return err
+	}
+
+	gen := generator.NewWithDistribution(s.lexicon, s.pos.Board, s.dist)
+	moves := gen.GenerateMovesParallel(s.pos.Racks[0], s.workers)
+	fmt.Fprintf(s.out, "%d moves\n", len(moves))
+
+	s.listed = moves[:min(n, len(moves))]
+	for i, move := range s.listed {
+		leave := s.leaveAfter(move)
+		fmt.Fprintf(s.out, "%3d. %-22s %4d  %s\n", i+1, move.Notation(s.dist.Alphabet()), move.Score, rackString(leave, s.dist.Alphabet()))
+	}
+	return nil
+}
+
+func (s *shell) evalCommand(args []string) error {
+	n, err := countArg(args, 0, defaultListed)
+	if err != nil {
+		return err
+	}
+	if err := s.ready(); err != nil {
+		return err
+	}
+	unseen, err := s.pos.Unseen()
+	if err != nil {
+		return err
+	}
+
+	rack := s.pos.Racks[0]
+	eval := evaluator.NewWithDistribution(unseen, evaluator.DefaultWeights, s.dist)
+	eval.SetBoard(s.pos.Board, s.lexicon)
+	eval.SetReplySamples(s.replies)
+	if s.leaves != nil {
+		eval.SetLeaves(s.leaves)
+	}
+
+	gen := generator.NewWithDistribution(s.lexicon, s.pos.Board, s.dist)
+	ranked := eval.BestMoves(gen, rack, s.pos.BagSize(), n)
+
+	s.listed = nil
+	fmt.Fprintf(s.out, "     %-22s %5s %7s  %s\n", "move", "score", "equity", "leave")
+	for i, r := range ranked {
+		move := r.Move
+		s.listed = append(s.listed, move)
+		fmt.Fprintf(s.out, "%3d. %-22s %5d %7.1f  %s\n", i+1, move.Notation(s.dist.Alphabet()), move.Score, r.Value, rackString(move.Leave, s.dist.Alphabet()))
+	}
+	return nil
+}
+
+func (s *shell) simCommand(args []string) error {
+	candidates, err := countArg(args, 0, defaultCandidates)
+	if err != nil {
+		return err
+	}
+	iterations, err := countArg(args, 1, defaultIterations)
+	if err != nil {
+		return err
+	}
+	if err := s.ready(); err != nil {
+		return err
+	}
+	unseen, err := s.pos.Unseen()
+	if err != nil {
+		return err
+	}
+
+	sim := simulator.New(s.lexicon, s.dist)
+	if s.leaves != nil {
+		sim.SetLeaves(s.leaves)
+	}
+	results := sim.Simulate(simulator.Position{
+		Board:  s.pos.Board,
+		Rack:   s.pos.Racks[0],
+		Unseen: unseen,
+		Spread: s.pos.Scores[0] - s.pos.Scores[1],
+	}, simulator.Config{Candidates: candidates, Iterations: iterations})
+
+	s.listed = nil
+	fmt.Fprintf(s.out, "     %-22s %5s %7s %7s %6s\n", "move", "score", "static", "spread", "win%")
+	for i, r := range results {
+		s.listed = append(s.listed, r.Move)
+		fmt.Fprintf(s.out, "%3d. %-22s %5d %7.1f %7.1f %6.1f\n", i+1, r.Move.Notation(s.dist.Alphabet()), r.Move.Score, r.Static, r.MeanSpread, r.WinPercent)
+	}
+	return nil
+}
+
+// playCommand puts a move on the board, takes its tiles off the rack and
+// adds its score to the mover's; then it is the opponent's turn, with their
+// rack and score first. Tiles not on the rack are skipped, so moves can be
+// entered for an opponent whose rack is unknown.
+func (s *shell) playCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: play MOVE|N")
+	}
+
+	var move game.Move
+	if n, err := strconv.Atoi(args[0]); err == nil && len(args) == 1 {
+		if n < 1 || n > len(s.listed) {
+			return fmt.Errorf("no move %d listed", n)
+		}
+		move = s.listed[n-1]
+	} else {
+		if move, err = game.ParseMove(strings.Join(args, " "), s.dist); err != nil {
+			return err
+		}
+		if move.Kind == game.MovePlace {
+			if move, err = s.checkPlacement(move); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.save()
+	s.pos.Board.Place(move)
+	s.pos.Scores[0] += move.Score
+	if move.Score == 0 {
+		s.pos.ZeroTurns++
+	} else {
+		s.pos.ZeroTurns = 0
+	}
+	s.pos.Racks = [2][]game.Tile{s.pos.Racks[1], s.leaveAfter(move)}
+	s.pos.Scores = [2]int{s.pos.Scores[1], s.pos.Scores[0]}
+	s.listed = nil
+	fmt.Fprintf(s.out, "Played %s for %d\n", move.Notation(s.dist.Alphabet()), move.Score)
+	return nil
+}
+
+// checkPlacement fits a typed placement to the board and scores it. Letters
+// typed over matching tiles already on the board are played through, so
+// parentheses are optional.
+func (s *shell) checkPlacement(move game.Move) (game.Move, error) {
+	if s.lexicon == nil {
+		return game.Move{}, fmt.Errorf("no lexicon loaded; use lexicon NAME")
+	}
+
+	placed, err := fitPlacement(s.pos.Board, move, s.dist.Alphabet())
+	if err != nil {
+		return game.Move{}, err
+	}
+
+	scored, err := validator.NewWithDistribution(s.lexicon, s.dist).ScorePlacement(s.pos.Board, placed)
+	if err != nil {
+		return game.Move{}, err
+	}
+	if len(scored.PhonyWords) > 0 {
+		return game.Move{}, fmt.Errorf("not in %s: %s", s.pos.Lexicon, s.dist.Alphabet().Decode(strings.Join(scored.PhonyWords, ", ")))
+	}
+	return scored.Move, nil
+}
+
+func (s *shell) undoCommand(args []string) error {
+	if len(s.history) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+	s.restore()
+	s.listed = nil
+	return nil
+}
+
+func (s *shell) showCommand(args []string) error {
+	s.show()
+	return nil
+}
+
+func (s *shell) show() {
+	alphabet := s.dist.Alphabet()
+	renderBoard(s.out, s.pos.Board, alphabet)
+
+	lexicon := s.pos.Lexicon
+	if s.lexicon == nil {
+		lexicon = "none"
+	}
+	fmt.Fprintf(s.out, "Rack: %-8s  Score: %d-%d  Lexicon: %s\n", rackString(s.pos.Racks[0], alphabet), s.pos.Scores[0], s.pos.Scores[1], lexicon)
+	if unseen, err := s.pos.Unseen(); err == nil {
+		total := 0
+		for _, count := range unseen {
+			total += count
+		}
+		fmt.Fprintf(s.out, "Unseen (%d): %s\n", total, unseenString(unseen, s.dist))
+	}
+}
+
+// loadCommand loads a game record, so undo steps back through its moves to
+// the position before the load.
+// The rack and scores are those of the player to move after the last event.
+func (s *shell) loadCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: load FILE.gcg")
+	}
+	record, err := gcg.LoadWithSetup(args[0], s.pos.Board.Layout(), s.dist)
+	if err != nil {
+		return err
+	}
+	pos, err := recordPosition(record, s.dist)
+	if err != nil {
+		return err
+	}
+
+	if record.Lexicon != "" && !strings.EqualFold(record.Lexicon, s.pos.Lexicon) {
+		if err := s.loadLexicon(record.Lexicon); err != nil {
+			fmt.Fprintf(s.out, "%v; keeping %s\n", err, s.pos.Lexicon)
+		}
+	}
+
+	s.save()
+	s.history = append(s.history, recordHistory(record, s.pos.Board.Layout(), s.dist)...)
+	pos.Lexicon = s.pos.Lexicon
+	s.pos = pos
+	s.listed = nil
+
+	fmt.Fprintf(s.out, "Loaded %s; %s to move\n", args[0], record.Players[record.ToMove()].Nickname)
+	return nil
+}
+
+// recordHistory returns the position before each move of a game record that
+// stands, as seen by the player making it. A withdrawn phony is dropped,
+// leaving only the lost turn.
+func recordHistory(record *gcg.Game, layout *board.Layout, dist *game.LetterDistribution) []snapshot {
+	var history []snapshot
+	b := board.NewWithLayout(layout)
+	var totals [2]int
+	zeroTurns := 0
+	for _, e := range record.Events {
+		switch e.Kind {
+		case gcg.EventPlay:
+			rack, _ := game.ParseRack(e.Rack, dist)
+			history = append(history, snapshot{
+				board:     b.Clone(),
+				racks:     [2][]game.Tile{rack, nil},
+				scores:    [2]int{totals[e.Player], totals[1-e.Player]},
+				zeroTurns: zeroTurns,
+			})
+			if e.Move.Kind == game.MovePlace {
+				b.Place(e.Move)
+			}
+			if e.Points == 0 {
+				zeroTurns++
+			} else {
+				zeroTurns = 0
+			}
+		case gcg.EventWithdrawn:
+			if len(history) > 0 {
+				last := history[len(history)-1]
+				history = history[:len(history)-1]
+				b, zeroTurns = last.board.Clone(), last.zeroTurns+1
+			}
+		}
+		totals[e.Player] = e.Total
+	}
+	return history
+}
+
+func (s *shell) cgpCommand(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(s.out, s.pos.String())
+		return nil
+	}
+
+	pos, err := cgp.ParseWithSetup(strings.Join(args, " "), s.pos.Board.Layout(), s.dist)
+	if err != nil {
+		return err
+	}
+	if pos.Lexicon != "" && !strings.EqualFold(pos.Lexicon, s.pos.Lexicon) {
+		if err := s.loadLexicon(pos.Lexicon); err != nil {
+			fmt.Fprintf(s.out, "%v; keeping %s\n", err, s.pos.Lexicon)
+		}
+	}
+	if pos.Lexicon == "" {
+		pos.Lexicon = s.pos.Lexicon
+	}
+
+	s.save()
+	s.pos = pos
+	s.listed = nil
+	s.show()
+	return nil
+}
+
+// ready checks there is a lexicon and a rack to find moves with
+func (s *shell) ready() error {
+	if s.lexicon == nil {
+		return fmt.Errorf("no lexicon loaded; use lexicon NAME")
+	}
+	if len(s.pos.Racks[0]) == 0 {
+		return fmt.Errorf("the rack is empty; use rack TILES")
+	}
+	return nil
+}
+
+// save records the position so the next change can be undone
+func (s *shell) save() {
+	s.history = append(s.history, snapshot{
+		board: s.pos.Board.Clone(),
+		racks: [2][]game.Tile{
+			append([]game.Tile(nil), s.pos.Racks[0]...),
+			append([]game.Tile(nil), s.pos.Racks[1]...),
+		},
+		scores:    s.pos.Scores,
+		zeroTurns: s.pos.ZeroTurns,
+	})
+}
+
+// restore returns to the last saved position
+func (s *shell) restore() {
+	last := s.history[len(s.history)-1]
+	s.history = s.history[:len(s.history)-1]
+	s.pos.Board, s.pos.Racks = last.board, last.racks
+	s.pos.Scores, s.pos.ZeroTurns = last.scores, last.zeroTurns
+}
+
+// parseRack reads tiles as a rack, with '?' for blanks
+func parseRack(tiles string, dist *game.LetterDistribution) ([]game.Tile, error) {
+	rack, err := game.ParseRack(tiles, dist)
+	if err != nil {
+		return nil, err
+	}
+	if len(rack) > cgp.RackSize {
+		return nil, fmt.Errorf("a rack holds at most %d tiles", cgp.RackSize)
+	}
+	return rack, nil
+}
+
+// fitPlacement returns the tiles a typed placement puts on the board. Letters
+// typed over matching tiles already on the board are played through.
+func fitPlacement(b *board.Board, move game.Move, alphabet *game.Alphabet) ([]game.PlacedTile, error) {
+	var placed []game.PlacedTile
+	for _, p := range move.TilesPlaced {
+		if !b.InBounds(p.Position.Row, p.Position.Col) {
+			return nil, fmt.Errorf("%s runs off the board", move.Notation(alphabet))
+		}
+		existing := b.GetTile(p.Position.Row, p.Position.Col)
+		switch {
+		case existing == nil:
+			placed = append(placed, p)
+		case existing.Letter != p.Tile.Letter:
+			return nil, fmt.Errorf("%s is already taken by %s", game.Move{Position: p.Position}.Coordinate(), alphabet.Display(existing.Letter))
+		}
+	}
+	return placed, nil
+}
+
+// countArg reads an optional positive count from args[i]
+func countArg(args []string, i, fallback int) (int, error) {
+	if i >= len(args) {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(args[i])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("bad count %q", args[i])
+	}
+	return n, nil
+}
+
+// leaveAfter returns the rack without the tiles a move uses. Tiles that are
+// not on the rack, such as the opponent's, are skipped.
+func (s *shell) leaveAfter(move game.Move) []game.Tile {
+	leave, _ := game.NewRack(s.dist, s.pos.Racks[0])
+	leave.RemoveTiles(move.TilesUsed())
+	return leave.Tiles()
+}
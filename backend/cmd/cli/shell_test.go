@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// newTestShell starts a shell with the tiny lexicon in testdata loaded
+func newTestShell(t *testing.T) (*shell, *bytes.Buffer) {
+	t.Helper()
+	var out bytes.Buffer
+	s := newShell(&out, "testdata", 1, 0)
+	if err := s.loadLexicon("tiny"); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	return s, &out
+}
+
+func TestShellRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string // All but the last must succeed
+		wantErr string   // From the last line; empty if it must succeed
+		wantOut string   // Printed by the last line
+	}{
+		{"unknown command", []string{"frobnicate"}, "unknown command", ""},
+		{"rack too long", []string{"rack ACTSACTS"}, "at most 7", ""},
+		{"nothing to undo", []string{"undo"}, "nothing to undo", ""},
+		{"gen needs a rack", []string{"gen"}, "rack is empty", ""},
+		{"gen lists moves", []string{"rack CATS", "gen 1"}, "", "  1. 8"},
+		{"eval lists moves", []string{"rack CATS", "eval 2"}, "", "2. "},
+		{"play a listed move", []string{"rack CATS", "gen 1", "play 1"}, "", "for 12"},
+		{"play a typed move", []string{"rack CATS", "play 8H CAT"}, "", "Score: 0-10"},
+		{"play a phony", []string{"rack CATS", "play 8H TAC"}, "not in tiny", ""},
+		{"play through a tile", []string{"rack CATS", "play 8H CAT", "play H8 CATS"}, "", "Played H8 (C)ATS for 6"},
+		{"load a leave table", []string{"leaves testdata/leaves.txt"}, "", "Loaded 3 leaves"},
+		{"eval with a leave table", []string{"leaves testdata/leaves.txt", "rack CATS", "eval 1"}, "", "1. "},
+		{"load a record", []string{"load testdata/opening.gcg"}, "", "bob to move"},
+		{"undo a loaded move", []string{"load testdata/opening.gcg", "undo"}, "", "Rack: ACTXYZQ   Score: 0-0"},
+		{"undo a load", []string{"rack CAT", "load testdata/opening.gcg", "undo", "undo"}, "", "Rack: CAT "},
+		{"rack of the player to move", []string{"load testdata/opening.gcg"}, "", "Rack: AST"},
+		{"cgp prints the position", []string{"rack CAT", "cgp"}, "", "15/15/15/15/15/15/15/15/15/15/15/15/15/15/15 CAT/ 0/0 0 lex tiny;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, out := newTestShell(t)
+			last := len(tt.lines) - 1
+			for _, line := range tt.lines[:last] {
+				if err := s.run(line); err != nil {
+					t.Fatalf("%s: %v", line, err)
+				}
+			}
+			out.Reset()
+
+			err := s.run(tt.lines[last])
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("%s: %v", tt.lines[last], err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("%s: got error %v, want one mentioning %q", tt.lines[last], err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("%s printed\n%s\nwant it to contain %q", tt.lines[last], out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestShellPlayPassesTurn(t *testing.T) {
+	s, _ := newTestShell(t)
+	for _, line := range []string{"rack CATS", "play 8H CAT"} {
+		if err := s.run(line); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+	}
+
+	if s.pos.Scores != [2]int{0, 10} {
+		t.Errorf("scores after CAT = %v, want the opponent to move at 0-10", s.pos.Scores)
+	}
+	if len(s.pos.Racks[0]) != 0 || rackString(s.pos.Racks[1], s.dist.Alphabet()) != "S" {
+		t.Errorf("racks after CAT = %v, want the opponent's unknown and S kept", s.pos.Racks)
+	}
+
+	if err := s.run("undo"); err != nil {
+		t.Fatal(err)
+	}
+	if s.pos.Scores != [2]int{} || rackString(s.pos.Racks[0], s.dist.Alphabet()) != "CATS" {
+		t.Errorf("after undo scores %v and rack %v, want 0-0 and CATS", s.pos.Scores, s.pos.Racks[0])
+	}
+	if s.pos.Board.GetTile(7, 7) != nil {
+		t.Error("undo left CAT on the board")
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"tiletactics/backend/internal/api"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/cgp"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/gcg"
+	"tiletactics/backend/internal/validator"
+)
+
+const (
+	defaultLexicon = "NWL2023"
+	defaultTop     = 20 // Moves listed by gen when no count is given
+)
+
+// Exit statuses of the subcommands
+const (
+	exitOK     = 0
+	exitFailed = 1 // An error, or words not in the lexicon
+	exitUsage  = 2
+)
+
+// subcommands answer one request and exit, for scripts, printing the answer
+// to stdout. With -json they print the same JSON the wasm build returns to
+// the browser.
+var subcommands = map[string]func(args []string, stdout io.Writer) int{
+	"gen":   genMain,
+	"check": checkMain,
+	"score": scoreMain,
+}
+
+// options are the flags every subcommand takes
+type options struct {
+	out     io.Writer
+	lexicon string
+	dictDir string
+	json    bool
+}
+
+// newFlagSet returns the flags for a subcommand, with the common ones set up
+func newFlagSet(name, usage string, stdout io.Writer) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tiletactics %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+
+	o := &options{out: stdout}
+	fs.StringVar(&o.lexicon, "lexicon", "", "lexicon, by name from the dictionary directory or by path (default the position's, else "+defaultLexicon+")")
+	fs.StringVar(&o.dictDir, "dictionaries", "dictionaries", "directory lexicons are looked up in by name")
+	fs.BoolVar(&o.json, "json", false, "print JSON in the shape the wasm build returns")
+	return fs, o
+}
+
+// openLexicon loads the lexicon named by -lexicon, or else by the position
+func (o *options) openLexicon(positionLexicon string, dist *game.LetterDistribution) (gaddag.Lexicon, error) {
+	name := o.lexicon
+	if name == "" {
+		name = positionLexicon
+	}
+	if name == "" {
+		name = defaultLexicon
+	}
+	lexicon, _, err := openLexicon(name, o.dictDir, dist.Alphabet())
+	return lexicon, err
+}
+
+// report prints response as JSON, or as text written by text
+func (o *options) report(response any, text func(w io.Writer)) {
+	if !o.json {
+		text(o.out)
+		return
+	}
+	enc := json.NewEncoder(o.out)
+	enc.SetIndent("", "  ")
+	enc.Encode(response)
+}
+
+// fail reports err, as response when printing JSON, and returns exitFailed
+func (o *options) fail(err error, response any) int {
+	if o.json {
+		o.report(response, nil)
+	} else {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return exitFailed
+}
+
+// genMain lists the best moves for a rack by static evaluation
+func genMain(args []string, stdout io.Writer) int {
+	fs, o := newFlagSet("gen", "[-board FILE] [-rack TILES] [-top N] [-json]", stdout)
+	boardFile := fs.String("board", "", "position to move from: a CGP file, the end of a .gcg record, or - to read CGP from standard input; empty board if unset")
+	rackTiles := fs.String("rack", "", "tiles on the rack, ? for a blank (default the position's rack)")
+	top := fs.Int("top", defaultTop, "number of moves listed")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 || *top < 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	fail := func(err error) int {
+		return o.fail(err, api.AnalysisResponse{Error: err.Error()})
+	}
+
+	dist := game.EnglishDistribution()
+	pos, err := readPosition(*boardFile, dist)
+	if err != nil {
+		return fail(err)
+	}
+	if *rackTiles != "" {
+		if pos.Racks[0], err = parseRack(*rackTiles, dist); err != nil {
+			return fail(err)
+		}
+	}
+	if len(pos.Racks[0]) == 0 {
+		return fail(errors.New("the rack is empty; use -rack TILES"))
+	}
+	unseen, err := pos.Unseen()
+	if err != nil {
+		return fail(err)
+	}
+	lexicon, err := o.openLexicon(pos.Lexicon, dist)
+	if err != nil {
+		return fail(err)
+	}
+
+	bagSize := pos.BagSize()
+	moves := api.BestMoves(lexicon, pos.Board, pos.Racks[0], unseen, dist, &bagSize, *top)
+	o.report(api.NewAnalysisResponse(moves, dist.Alphabet()), func(w io.Writer) {
+		for i, move := range moves {
+			fmt.Fprintf(w, "%3d. %-22s %4d  %s\n", i+1, move.Notation(dist.Alphabet()), move.Score, rackString(move.Leave, dist.Alphabet()))
+		}
+	})
+	return exitOK
+}
+
+// checkMain looks words up in the lexicon, failing if any is not there
+func checkMain(args []string, stdout io.Writer) int {
+	fs, o := newFlagSet("check", "[-json] WORD...", stdout)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	dist := game.EnglishDistribution()
+	lexicon, err := o.openLexicon("", dist)
+	if err != nil {
+		return o.fail(err, api.ValidationResponse{Error: err.Error()})
+	}
+
+	response := api.ValidateWords(lexicon, dist.Alphabet(), fs.Args())
+	o.report(response, func(w io.Writer) {
+		for _, result := range response.Results {
+			verdict := "valid"
+			if !result.IsValid {
+				verdict = "invalid"
+			}
+			fmt.Fprintf(w, "%-15s %s\n", result.Word, verdict)
+		}
+	})
+	if !response.AllValid {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// scoreMain scores a placement and checks the words it forms
+func scoreMain(args []string, stdout io.Writer) int {
+	fs, o := newFlagSet("score", "-move MOVE [-board FILE] [-json]", stdout)
+	notation := fs.String("move", "", "placement to score, such as 8H:QUIXOTIC or \"8G Q(U)IXOTIc\"")
+	boardFile := fs.String("board", "", "position to play on: a CGP file, the end of a .gcg record, or - to read CGP from standard input; empty board if unset")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 || *notation == "" {
+		fs.Usage()
+		return exitUsage
+	}
+	fail := func(err error) int {
+		return o.fail(err, api.PlacementResponse{Error: err.Error(), Reason: api.RejectionReason(err)})
+	}
+
+	dist := game.EnglishDistribution()
+	alphabet := dist.Alphabet()
+	pos, err := readPosition(*boardFile, dist)
+	if err != nil {
+		return fail(err)
+	}
+	move, err := game.ParseMove(*notation, dist)
+	if err != nil {
+		return fail(err)
+	}
+	if move.Kind != game.MovePlace {
+		return fail(errors.New("only placements can be scored"))
+	}
+	placed, err := fitPlacement(pos.Board, move, alphabet)
+	if err != nil {
+		return fail(err)
+	}
+	lexicon, err := o.openLexicon(pos.Lexicon, dist)
+	if err != nil {
+		return fail(err)
+	}
+
+	scored, err := validator.NewWithDistribution(lexicon, dist).ScorePlacement(pos.Board, placed)
+	if err != nil {
+		return fail(err)
+	}
+	response := api.NewPlacementResponse(scored, alphabet)
+	o.report(response, func(w io.Writer) {
+		fmt.Fprintf(w, "%s %d\n", scored.Move.Notation(alphabet), scored.Move.Score)
+		for _, word := range response.Words {
+			verdict := ""
+			if !word.IsValid {
+				verdict = "  invalid"
+			}
+			fmt.Fprintf(w, "  %-15s %4d%s\n", word.Word, word.Score, verdict)
+		}
+		if response.Bingo > 0 {
+			fmt.Fprintf(w, "  %-15s %4d\n", "bingo", response.Bingo)
+		}
+	})
+	if !response.AllValid {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// readPosition reads the position a subcommand works on. A .gcg file gives
+// the position after its last event, with the rack of the player to move;
+// anything else is read as a CGP string, from standard input for "-".
+func readPosition(filename string, dist *game.LetterDistribution) (*cgp.Position, error) {
+	switch {
+	case filename == "":
+		return cgp.NewWithDistribution(board.New(), nil, dist), nil
+	case filepath.Ext(filename) == ".gcg":
+		return positionFromRecord(filename, dist)
+	}
+
+	var data []byte
+	var err error
+	if filename == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pos, err := cgp.ParseWithSetup(strings.TrimSpace(string(data)), board.StandardLayout(), dist)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return pos, nil
+}
+
+// positionFromRecord returns the position at the end of a game record
+func positionFromRecord(filename string, dist *game.LetterDistribution) (*cgp.Position, error) {
+	record, err := gcg.LoadWithSetup(filename, board.StandardLayout(), dist)
+	if err != nil {
+		return nil, err
+	}
+	return recordPosition(record, dist)
+}
+
+// recordPosition returns the position after the last event of a record, as
+// the player to move sees it
+func recordPosition(record *gcg.Game, dist *game.LetterDistribution) (*cgp.Position, error) {
+	toMove := record.ToMove()
+	rack, err := game.ParseRack(record.Rack(toMove), dist)
+	if err != nil {
+		return nil, fmt.Errorf("rack of %s: %w", record.Players[toMove].Nickname, err)
+	}
+
+	pos := cgp.NewWithDistribution(record.Board(), rack, dist)
+	pos.Scores = [2]int{record.Score(toMove), record.Score(1 - toMove)}
+	pos.Lexicon = record.Lexicon
+	return pos, nil
+}
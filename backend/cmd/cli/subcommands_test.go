@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"tiletactics/backend/internal/api"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestGenJSON(t *testing.T) {
+	var out bytes.Buffer
+	args := []string{"-dictionaries", "testdata", "-board", "testdata/opening.gcg", "-rack", "ACTS", "-top", "3", "-json"}
+	if status := genMain(args, &out); status != exitOK {
+		t.Fatalf("gen exited with %d:\n%s", status, out.String())
+	}
+
+	// The output is what the wasm build returns, field for field
+	var response api.AnalysisResponse
+	dec := json.NewDecoder(bytes.NewReader(out.Bytes()))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&response); err != nil {
+		t.Fatalf("output does not decode as an AnalysisResponse: %v", err)
+	}
+	if response.Error != "" || len(response.Moves) != 3 {
+		t.Errorf("got %d moves and error %q, want 3 moves", len(response.Moves), response.Error)
+	}
+
+	const golden = "testdata/gen.golden"
+	if *update {
+		if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("gen -json printed\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestCheckAndScore(t *testing.T) {
+	lexicon := []string{"-dictionaries", "testdata", "-lexicon", "tiny"}
+	tests := []struct {
+		name       string
+		run        func([]string, io.Writer) int
+		args       []string
+		wantStatus int
+		wantOut    string
+		response   any // What -json output must decode into, field for field
+	}{
+		{"valid word", checkMain, []string{"CAT"}, exitOK, "CAT             valid", nil},
+		{"invalid word", checkMain, []string{"CAT", "TAC"}, exitFailed, "TAC             invalid", nil},
+		{"valid word as json", checkMain, []string{"-json", "CAT"}, exitOK, `"allValid": true`, &api.ValidationResponse{}},
+		{"invalid word as json", checkMain, []string{"-json", "TAC"}, exitFailed, `"invalidWords": [`, &api.ValidationResponse{}},
+		{"no words", checkMain, nil, exitUsage, "", nil},
+		{"score a play", scoreMain, []string{"-move", "8H:CATS"}, exitOK, "8H CATS 12", nil},
+		{"score a play as json", scoreMain, []string{"-json", "-move", "8H:CATS"}, exitOK, `"score": 12`, &api.PlacementResponse{}},
+		{"score a phony", scoreMain, []string{"-move", "8H:TAC"}, exitFailed, "TAC               10  invalid", nil},
+		{"score a phony as json", scoreMain, []string{"-json", "-move", "8H:TAC"}, exitFailed, `"allValid": false`, &api.PlacementResponse{}},
+		{"score without a move", scoreMain, nil, exitUsage, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			status := tt.run(append(append([]string(nil), lexicon...), tt.args...), &out)
+			if status != tt.wantStatus {
+				t.Errorf("exited with %d, want %d", status, tt.wantStatus)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("printed\n%s\nwant it to contain %q", out.String(), tt.wantOut)
+			}
+
+			if tt.response != nil {
+				dec := json.NewDecoder(bytes.NewReader(out.Bytes()))
+				dec.DisallowUnknownFields()
+				if err := dec.Decode(tt.response); err != nil {
+					t.Errorf("output does not decode as %T: %v", tt.response, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Command gaddagc precompiles word lists into compact GADDAG files that the
+// WASM module can load without rebuilding the trie.
+//
+// Usage:
+//
+//	gaddagc [-o dir] [-distribution file] dictionaries/*.txt
+//
+// Each input NAME.txt is written to NAME.gaddag in the output directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"time"
+)
+
+func main() {
+	outDir := flag.String("o", "", "output directory (default: next to each input)")
+	distFile := flag.String("distribution", "", "letter distribution whose alphabet spells the words (default: English)")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: gaddagc [-o dir] [-distribution file] dictionary.txt...")
+		os.Exit(2)
+	}
+
+	alphabet := game.EnglishAlphabet()
+	if *distFile != "" {
+		dist, err := game.LoadDistribution(*distFile)
+		if err != nil {
+			log.Fatalf("Failed to load distribution: %v", err)
+		}
+		alphabet = dist.Alphabet()
+	}
+
+	for _, input := range flag.Args() {
+		dir := filepath.Dir(input)
+		if *outDir != "" {
+			dir = *outDir
+		}
+		output := filepath.Join(dir, strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))+".gaddag")
+
+		if err := compile(input, output, alphabet); err != nil {
+			log.Fatalf("%s: %v", input, err)
+		}
+	}
+}
+
+// compile builds the GADDAG for one word list and writes its compact form
+func compile(input, output string, alphabet *game.Alphabet) error {
+	start := time.Now()
+
+	g, err := gaddag.LoadWithAlphabet(input, alphabet)
+	if err != nil {
+		return err
+	}
+	g.Minimize()
+	c := g.Compact()
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create output: %w", err)
+	}
+	size, err := c.WriteTo(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s -> %s: %s, %d bytes in %v\n", input, output, c.Stats(), size, time.Since(start).Round(time.Millisecond))
+	return nil
+}
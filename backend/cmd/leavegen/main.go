@@ -0,0 +1,233 @@
+// Command leavegen estimates how much each leave is worth by self-play.
+// Both players pick moves with the evaluator, and every leave of one to six
+// tiles kept while the bag still has tiles is credited with the score its
+// owner makes on their next turn. A leave's value is how far that score sits
+// above the average next-turn score, pulled towards what its sub-leaves
+// suggest when it has been seen only a few times.
+//
+// Usage:
+//
+//	leavegen [-games n] [-workers n] [-leaves start.txt] [-o leaves.txt] lexicon
+//
+// The lexicon is a word list or a compiled .gaddag file. Passing the output
+// back in with -leaves and running again refines the table.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/engine"
+	"tiletactics/backend/internal/evaluator"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+	"time"
+)
+
+func main() {
+	games := flag.Int("games", 1000, "number of self-play games")
+	seed := flag.Int64("seed", 1, "seed of the first game; game i uses seed+i")
+	workers := flag.Int("workers", 0, "games played at once; 0 means one per CPU")
+	smoothing := flag.Float64("smooth", 20, "samples a leave needs before its own results count as much as its sub-leaves'")
+	leavesFile := flag.String("leaves", "", "leave table the players start from (default: hand-tuned values)")
+	distFile := flag.String("distribution", "", "letter distribution file (default: English)")
+	output := flag.String("o", "leaves.txt", "output file")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: leavegen [flags] lexicon")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	dist := game.EnglishDistribution()
+	if *distFile != "" {
+		var err error
+		if dist, err = game.LoadDistribution(*distFile); err != nil {
+			log.Fatalf("Failed to load distribution: %v", err)
+		}
+	}
+
+	lexicon, err := loadLexicon(flag.Arg(0), dist)
+	if err != nil {
+		log.Fatalf("Failed to load lexicon: %v", err)
+	}
+
+	var start *evaluator.LeaveTable
+	if *leavesFile != "" {
+		if start, err = evaluator.LoadLeaves(*leavesFile); err != nil {
+			log.Fatalf("Failed to load leaves: %v", err)
+		}
+	}
+
+	if *workers <= 0 {
+		*workers = runtime.GOMAXPROCS(0)
+	}
+
+	began := time.Now()
+	stats := newLeaveStats(dist)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				playGame(lexicon, dist, start, *seed+int64(i), stats)
+			}
+		}()
+	}
+	for i := 0; i < *games; i++ {
+		next <- i
+		if step := *games / 10; step > 0 && i > 0 && i%step == 0 {
+			fmt.Printf("%d/%d games, %v\n", i, *games, time.Since(began).Round(time.Second))
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	table := stats.table(*smoothing)
+	if err := writeTable(*output, table); err != nil {
+		log.Fatalf("Failed to write leaves: %v", err)
+	}
+	fmt.Printf("%d games, %d turns: wrote %d leaves to %s in %v\n",
+		*games, stats.turns, table.Len(), *output, time.Since(began).Round(time.Second))
+}
+
+// loadLexicon reads a compiled .gaddag file or builds one from a word list
+func loadLexicon(filename string, dist *game.LetterDistribution) (gaddag.Lexicon, error) {
+	if filepath.Ext(filename) == ".gaddag" {
+		return gaddag.LoadCompact(filename)
+	}
+	g, err := gaddag.LoadWithAlphabet(filename, dist.Alphabet())
+	if err != nil {
+		return nil, err
+	}
+	g.Minimize()
+	return g.Compact(), nil
+}
+
+// playGame plays one self-play game and records its leaves
+func playGame(lexicon gaddag.Lexicon, dist *game.LetterDistribution, leaves *evaluator.LeaveTable, seed int64, stats *leaveStats) {
+	g := engine.NewWithSetup(lexicon, board.StandardLayout(), dist, seed)
+
+	// The leave each player kept last turn, waiting for their next score
+	var pending [engine.NumPlayers][]game.Tile
+
+	for !g.IsOver() {
+		player := g.CurrentPlayer()
+		move := bestMove(g, lexicon, dist, leaves)
+
+		if pending[player] != nil {
+			stats.add(pending[player], move.Score)
+			pending[player] = nil
+		}
+
+		if err := g.Apply(move); err != nil {
+			log.Fatalf("game %d: generated move was rejected: %v", seed, err)
+		}
+
+		// Leaves only matter while there are tiles to draw to them
+		if n := len(move.Leave); n > 0 && n < engine.RackSize && g.BagLen() > 0 {
+			pending[player] = move.Leave
+		}
+	}
+}
+
+// bestMove picks the current player's move with the evaluator
+func bestMove(g *engine.Game, lexicon gaddag.Lexicon, dist *game.LetterDistribution, leaves *evaluator.LeaveTable) game.Move {
+	player := g.CurrentPlayer()
+	rack := g.Rack(player)
+
+	eval := evaluator.NewWithDistribution(g.Unseen(player), evaluator.DefaultWeights, dist)
+	if leaves != nil {
+		eval.SetLeaves(leaves)
+	}
+
+	gen := generator.NewWithDistribution(lexicon, g.Board(), dist)
+	return eval.BestMoves(gen, rack, g.BagLen(), 1)[0].Move
+}
+
+// leaveStats totals the next-turn scores following each leave
+type leaveStats struct {
+	mu     sync.Mutex
+	dist   *game.LetterDistribution
+	leaves map[string][]game.Tile
+	sums   map[string]float64
+	counts map[string]int
+	total  float64
+	turns  int
+}
+
+func newLeaveStats(dist *game.LetterDistribution) *leaveStats {
+	return &leaveStats{
+		dist:   dist,
+		leaves: make(map[string][]game.Tile),
+		sums:   make(map[string]float64),
+		counts: make(map[string]int),
+	}
+}
+
+func (s *leaveStats) add(leave []game.Tile, score int) {
+	key := evaluator.LeaveKey(leave, s.dist)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.leaves[key] = leave
+	s.sums[key] += float64(score)
+	s.counts[key]++
+	s.total += float64(score)
+	s.turns++
+}
+
+// table turns the totals into leave values relative to the average turn.
+// Shortest leaves come first, so each leave's own average can be blended
+// with the estimate from its sub-leaves, weighted as if that estimate were
+// smoothing samples. Rare leaves then stay on the table's scale rather
+// than taking the noisy average of a handful of turns.
+func (s *leaveStats) table(smoothing float64) *evaluator.LeaveTable {
+	table := evaluator.NewLeaveTable()
+	if s.turns == 0 {
+		return table
+	}
+
+	keys := make([]string, 0, len(s.counts))
+	for key := range s.counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(s.leaves[keys[i]]) != len(s.leaves[keys[j]]) {
+			return len(s.leaves[keys[i]]) < len(s.leaves[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+
+	average := s.total / float64(s.turns)
+	for _, key := range keys {
+		count := float64(s.counts[key])
+		prior, _ := table.Estimate(s.leaves[key], s.dist) // Zero, the average, when there is nothing to go on
+		table.Set(key, (s.sums[key]-count*average+smoothing*prior)/(count+smoothing))
+	}
+	return table
+}
+
+func writeTable(filename string, table *evaluator.LeaveTable) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(file, "# leave value, in points relative to an average turn; written by leavegen")
+	_, err = table.WriteTo(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
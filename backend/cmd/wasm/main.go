@@ -4,42 +4,28 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"syscall/js"
+	"tiletactics/backend/internal/api"
 	"tiletactics/backend/internal/board"
-	"tiletactics/backend/internal/evaluator"
+	"tiletactics/backend/internal/cgp"
 	"tiletactics/backend/internal/gaddag"
 	"tiletactics/backend/internal/game"
-	"tiletactics/backend/internal/generator"
+	"tiletactics/backend/internal/validator"
 )
 
 // Global GADDAG to avoid reloading
-var gaddagCache map[string]*gaddag.GADDAG
+var gaddagCache map[string]gaddag.Lexicon
 
-func init() {
-	gaddagCache = make(map[string]*gaddag.GADDAG)
-}
-
-// ValidationRequest represents word validation input
-type ValidationRequest struct {
-	Words      []string `json:"words"`
-	Dictionary string   `json:"dictionary"`
-}
-
-// ValidationResponse represents word validation output
-type ValidationResponse struct {
-	Results      []WordValidation `json:"results"`
-	AllValid     bool             `json:"allValid"`
-	InvalidWords []string         `json:"invalidWords,omitempty"`
-	Error        string           `json:"error,omitempty"`
-}
+// Letter distributions fetched so far, keyed by name
+var distributionCache map[string]*game.LetterDistribution
 
-// WordValidation represents validation result for a single word
-type WordValidation struct {
-	Word    string `json:"word"`
-	IsValid bool   `json:"isValid"`
+func init() {
+	gaddagCache = make(map[string]gaddag.Lexicon)
+	distributionCache = make(map[string]*game.LetterDistribution)
 }
 
 // validateWords validates a list of words against the dictionary
@@ -48,7 +34,7 @@ func validateWords(this js.Value, args []js.Value) (result interface{}) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Printf("Panic in validateWords: %v\n", r)
-			response := ValidationResponse{
+			response := api.ValidationResponse{
 				Error: fmt.Sprintf("Internal error: %v", r),
 			}
 			responseJSON, _ := json.Marshal(response)
@@ -63,7 +49,7 @@ func validateWords(this js.Value, args []js.Value) (result interface{}) {
 	}
 
 	jsonStr := args[0].String()
-	var request ValidationRequest
+	var request api.ValidationRequest
 	if err := json.Unmarshal([]byte(jsonStr), &request); err != nil {
 		return createValidationErrorResponse(fmt.Sprintf("Failed to parse request: %v", err))
 	}
@@ -74,61 +60,18 @@ func validateWords(this js.Value, args []js.Value) (result interface{}) {
 		return createValidationErrorResponse("No words to validate")
 	}
 
-	// Load or get cached GADDAG
-	g, err := getGaddag(request.Dictionary)
+	dist, err := getDistribution(request.Distribution)
 	if err != nil {
-		return createValidationErrorResponse(fmt.Sprintf("Failed to load dictionary: %v", err))
+		return createValidationErrorResponse(fmt.Sprintf("Failed to load distribution: %v", err))
 	}
 
-	// Validate each word
-	results := make([]WordValidation, len(request.Words))
-	invalidWords := []string{}
-	allValid := true
-
-	for i, word := range request.Words {
-		// Skip empty words
-		if word == "" {
-			results[i] = WordValidation{
-				Word:    word,
-				IsValid: false,
-			}
-			allValid = false
-			invalidWords = append(invalidWords, word)
-			continue
-		}
-
-		// Handle blanks - blanks are represented as lowercase in the word
-		// Convert word with blanks to check format
-		checkWord := ""
-		for _, ch := range word {
-			if ch >= 'a' && ch <= 'z' {
-				// Lowercase indicates blank tile - convert to uppercase for checking
-				checkWord += strings.ToUpper(string(ch))
-			} else {
-				checkWord += string(ch)
-			}
-		}
-
-		// Check if word exists in dictionary
-		isValid := g.Contains(checkWord)
-
-		results[i] = WordValidation{
-			Word:    word,
-			IsValid: isValid,
-		}
-
-		if !isValid {
-			allValid = false
-			invalidWords = append(invalidWords, word)
-		}
+	// Load or get cached GADDAG
+	g, err := getGaddag(request.Dictionary, dist)
+	if err != nil {
+		return createValidationErrorResponse(fmt.Sprintf("Failed to load dictionary: %v", err))
 	}
 
-	// Create response
-	response := ValidationResponse{
-		Results:      results,
-		AllValid:     allValid,
-		InvalidWords: invalidWords,
-	}
+	response := api.ValidateWords(g, dist.Alphabet(), request.Words)
 
 	// Return JSON response
 	responseJSON, _ := json.Marshal(response)
@@ -137,54 +80,11 @@ func validateWords(this js.Value, args []js.Value) (result interface{}) {
 
 // createValidationErrorResponse creates an error response for validation
 func createValidationErrorResponse(error string) string {
-	response := ValidationResponse{Error: error}
+	response := api.ValidationResponse{Error: error}
 	responseJSON, _ := json.Marshal(response)
 	return string(responseJSON)
 }
 
-// AnalysisRequest represents the input from JavaScript
-type AnalysisRequest struct {
-	Board          [][]TileJSON   `json:"board"`
-	Rack           []TileJSON     `json:"rack"`
-	RemainingTiles map[string]int `json:"remainingTiles"`
-	Dictionary     string         `json:"dictionary"`
-}
-
-// TileJSON represents a tile in JSON format
-type TileJSON struct {
-	Letter  string `json:"letter"`
-	Value   int    `json:"value"`
-	IsBlank bool   `json:"isBlank"`
-}
-
-// AnalysisResponse represents the output to JavaScript
-type AnalysisResponse struct {
-	Moves []MoveJSON `json:"moves"`
-	Error string     `json:"error,omitempty"`
-}
-
-// MoveJSON represents a move in JSON format
-type MoveJSON struct {
-	Word        string           `json:"word"`
-	Position    PositionJSON     `json:"position"`
-	Direction   string           `json:"direction"`
-	Score       int              `json:"score"`
-	TilesPlaced []PlacedTileJSON `json:"tilesPlaced"`
-	Leave       []TileJSON       `json:"leave"`
-}
-
-// PositionJSON represents a position in JSON format
-type PositionJSON struct {
-	Row int `json:"row"`
-	Col int `json:"col"`
-}
-
-// PlacedTileJSON represents a placed tile in JSON format
-type PlacedTileJSON struct {
-	Position PositionJSON `json:"position"`
-	Tile     TileJSON     `json:"tile"`
-}
-
 // analyzePosition is the main function exposed to JavaScript
 func analyzePosition(this js.Value, args []js.Value) (result interface{}) {
 	// Always return something, even on panic
@@ -194,7 +94,7 @@ func analyzePosition(this js.Value, args []js.Value) (result interface{}) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Printf("Panic in analyzePosition: %v\n", r)
-			response := AnalysisResponse{
+			response := api.AnalysisResponse{
 				Error: fmt.Sprintf("Internal error: %v", r),
 			}
 			responseJSON, _ := json.Marshal(response)
@@ -213,178 +113,214 @@ func analyzePosition(this js.Value, args []js.Value) (result interface{}) {
 		return createErrorResponse("Empty request")
 	}
 
-	var request AnalysisRequest
+	var request api.AnalysisRequest
 	if err := json.Unmarshal([]byte(jsonStr), &request); err != nil {
 		return createErrorResponse(fmt.Sprintf("Failed to parse request: %v", err))
 	}
 
-	// Validate rack is not empty
-	if len(request.Rack) == 0 {
-		// Return empty moves list if no tiles in rack
-		response := AnalysisResponse{
-			Moves: []MoveJSON{},
-		}
-		responseJSON, _ := json.Marshal(response)
-		return string(responseJSON)
+	dist, err := getDistribution(request.Distribution)
+	if err != nil {
+		return createErrorResponse(fmt.Sprintf("Failed to load distribution: %v", err))
 	}
+	alphabet := dist.Alphabet()
 
-	// Load or get cached GADDAG
-	g, err := getGaddag(request.Dictionary)
+	layout, err := getLayout(request.Layout, request.CustomLayout)
 	if err != nil {
-		return createErrorResponse(fmt.Sprintf("Failed to load dictionary: %v", err))
+		return createErrorResponse(err.Error())
 	}
 
-	// Convert board from JSON
-	b := board.New()
-	for row := 0; row < len(request.Board); row++ {
-		for col := 0; col < len(request.Board[row]); col++ {
-			tileJSON := request.Board[row][col]
-			if tileJSON.Letter != "" {
-				tile := &game.Tile{
-					Letter:  rune(tileJSON.Letter[0]),
-					Value:   tileJSON.Value,
-					IsBlank: tileJSON.IsBlank,
+	var b *board.Board
+	var rack []game.Tile
+	remainingTiles := make(map[rune]int)
+	if request.CGP != "" {
+		// The position string stands in for the board, rack and remaining tiles
+		pos, err := cgp.ParseWithSetup(request.CGP, layout, dist)
+		if err != nil {
+			return createErrorResponse(fmt.Sprintf("Failed to parse position: %v", err))
+		}
+		b, rack = pos.Board, pos.Racks[0]
+		if remainingTiles, err = pos.Unseen(); err != nil {
+			return createErrorResponse(err.Error())
+		}
+		if request.Dictionary == "" {
+			request.Dictionary = pos.Lexicon
+		}
+		if request.BagSize == nil {
+			bagSize := pos.BagSize()
+			request.BagSize = &bagSize
+		}
+	} else {
+		// Convert board from JSON
+		if b, err = api.BoardFromJSON(request.Board, layout, alphabet); err != nil {
+			return createErrorResponse(err.Error())
+		}
+
+		// Convert rack from JSON - handle empty letters
+		rack = make([]game.Tile, 0, len(request.Rack))
+		for _, tileJSON := range request.Rack {
+			if tileJSON.Letter == "" {
+				// Handle blank tiles
+				rack = append(rack, game.Tile{
+					Letter:  '?',
+					Value:   0,
+					IsBlank: true,
+				})
+			} else {
+				tile, err := api.TileFromJSON(tileJSON, alphabet)
+				if err != nil {
+					return createErrorResponse(err.Error())
 				}
-				b.SetTile(row, col, tile)
+				rack = append(rack, tile)
 			}
 		}
-	}
 
-	// Convert rack from JSON - handle empty letters
-	rack := make([]game.Tile, 0, len(request.Rack))
-	for _, tileJSON := range request.Rack {
-		if tileJSON.Letter == "" {
-			// Handle blank tiles
-			rack = append(rack, game.Tile{
-				Letter:  '?',
-				Value:   0,
-				IsBlank: true,
-			})
-		} else {
-			rack = append(rack, game.Tile{
-				Letter:  rune(tileJSON.Letter[0]),
-				Value:   tileJSON.Value,
-				IsBlank: tileJSON.IsBlank,
-			})
+		// Convert remaining tiles
+		for letter, count := range request.RemainingTiles {
+			if letter == "?" {
+				remainingTiles['?'] = count
+			} else if code, ok := alphabet.Code(letter); ok {
+				remainingTiles[code] = count
+			}
 		}
 	}
 
-	// If rack is still empty after processing, return no moves
+	// If the rack is empty, return no moves
 	if len(rack) == 0 {
-		response := AnalysisResponse{
-			Moves: []MoveJSON{},
+		response := api.AnalysisResponse{
+			Moves: []api.MoveJSON{},
 		}
 		responseJSON, _ := json.Marshal(response)
 		return string(responseJSON)
 	}
 
-	// Convert remaining tiles
-	remainingTiles := make(map[rune]int)
-	for letter, count := range request.RemainingTiles {
-		if letter == "?" {
-			remainingTiles['?'] = count
-		} else if len(letter) > 0 {
-			remainingTiles[rune(letter[0])] = count
-		}
+	// Load or get cached GADDAG
+	g, err := getGaddag(request.Dictionary, dist)
+	if err != nil {
+		return createErrorResponse(fmt.Sprintf("Failed to load dictionary: %v", err))
 	}
 
-	// Generate moves
-	gen := generator.New(g, b)
-	allMoves := gen.GenerateMoves(rack)
+	// Keep the top 10 moves, converted to JSON format
+	response := api.NewAnalysisResponse(api.BestMoves(g, b, rack, remainingTiles, dist, request.BagSize, 10), alphabet)
 
-	// If no moves found, return empty list
-	if len(allMoves) == 0 {
-		response := AnalysisResponse{
-			Moves: []MoveJSON{},
-		}
-		responseJSON, _ := json.Marshal(response)
-		return string(responseJSON)
+	// Return JSON response
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return createErrorResponse(fmt.Sprintf("Failed to marshal response: %v", err))
 	}
 
-	// Evaluate moves
-	eval := evaluator.New(remainingTiles)
-	bestMoves := eval.EvaluateMoves(allMoves, rack, 10) // Return top 10
-
-	// Convert moves to JSON format
-	response := AnalysisResponse{
-		Moves: make([]MoveJSON, len(bestMoves)),
-	}
+	return string(responseJSON)
+}
 
-	for i, move := range bestMoves {
-		moveJSON := MoveJSON{
-			Word:     move.Word,
-			Position: PositionJSON{Row: move.Position.Row, Col: move.Position.Col},
-			Score:    move.Score,
+// scorePlacement scores tiles placed by a human player, inferring the main
+// word and direction and validating every word formed
+func scorePlacement(this js.Value, args []js.Value) (result interface{}) {
+	// Wrap in panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("Panic in scorePlacement: %v\n", r)
+			response := api.PlacementResponse{
+				Error: fmt.Sprintf("Internal error: %v", r),
+			}
+			responseJSON, _ := json.Marshal(response)
+			js.Global().Get("console").Call("error", "WASM panic:", r)
+			result = string(responseJSON)
 		}
+	}()
 
-		// Set direction
-		if move.Direction == game.Horizontal {
-			moveJSON.Direction = "H"
-		} else {
-			moveJSON.Direction = "V"
-		}
+	// Parse input
+	if len(args) != 1 {
+		return createPlacementErrorResponse("Expected 1 argument", "")
+	}
 
-		// Convert tiles placed
-		moveJSON.TilesPlaced = make([]PlacedTileJSON, len(move.TilesPlaced))
-		for j, placed := range move.TilesPlaced {
-			// Keep the letter even for blank tiles
-			// Blank tiles should have their designated letter (what they represent)
-			letter := string(placed.Tile.Letter)
+	var request api.PlacementRequest
+	if err := json.Unmarshal([]byte(args[0].String()), &request); err != nil {
+		return createPlacementErrorResponse(fmt.Sprintf("Failed to parse request: %v", err), "")
+	}
 
-			// Only set to empty if there's truly no letter (which shouldn't happen in valid moves)
-			if placed.Tile.Letter == 0 {
-				letter = ""
-			}
+	dist, err := getDistribution(request.Distribution)
+	if err != nil {
+		return createPlacementErrorResponse(fmt.Sprintf("Failed to load distribution: %v", err), "")
+	}
+	alphabet := dist.Alphabet()
 
-			moveJSON.TilesPlaced[j] = PlacedTileJSON{
-				Position: PositionJSON{Row: placed.Position.Row, Col: placed.Position.Col},
-				Tile: TileJSON{
-					Letter:  letter, // Keeps the letter for blanks
-					Value:   placed.Tile.Value,
-					IsBlank: placed.Tile.IsBlank,
-				},
-			}
-		}
+	// Load or get cached GADDAG
+	g, err := getGaddag(request.Dictionary, dist)
+	if err != nil {
+		return createPlacementErrorResponse(fmt.Sprintf("Failed to load dictionary: %v", err), "")
+	}
 
-		// Convert leave
-		moveJSON.Leave = make([]TileJSON, len(move.Leave))
-		for j, tile := range move.Leave {
-			letter := string(tile.Letter)
-
-			// For leave tiles, blanks might be represented as '?'
-			// Keep the letter as-is unless it's truly empty
-			if tile.Letter == 0 {
-				letter = ""
-			} else if tile.IsBlank && tile.Letter == '?' {
-				// Leave blanks as '?' in the leave
-				letter = "?"
-			}
+	layout, err := getLayout(request.Layout, request.CustomLayout)
+	if err != nil {
+		return createPlacementErrorResponse(err.Error(), "")
+	}
+	b, err := api.BoardFromJSON(request.Board, layout, alphabet)
+	if err != nil {
+		return createPlacementErrorResponse(err.Error(), "")
+	}
 
-			moveJSON.Leave[j] = TileJSON{
-				Letter:  letter,
-				Value:   tile.Value,
-				IsBlank: tile.IsBlank,
-			}
+	placed := make([]game.PlacedTile, 0, len(request.Tiles))
+	if request.Move != "" {
+		move, err := game.ParseMove(request.Move, dist)
+		if err != nil {
+			return createPlacementErrorResponse(err.Error(), "")
 		}
-
-		response.Moves[i] = moveJSON
+		if move.Kind != game.MovePlace {
+			return createPlacementErrorResponse("Only placements can be scored", "")
+		}
+		placed = move.TilesPlaced
+	}
+	for _, tileJSON := range request.Tiles {
+		if tileJSON.Tile.Letter == "" {
+			return createPlacementErrorResponse("Blank tile has no letter assigned", "")
+		}
+		tile, err := api.TileFromJSON(tileJSON.Tile, alphabet)
+		if err != nil {
+			return createPlacementErrorResponse(err.Error(), "")
+		}
+		placed = append(placed, game.PlacedTile{
+			Position: game.Position{Row: tileJSON.Position.Row, Col: tileJSON.Position.Col},
+			Tile:     tile,
+		})
 	}
 
-	// Return JSON response
-	responseJSON, err := json.Marshal(response)
+	scored, err := validator.NewWithDistribution(g, dist).ScorePlacement(b, placed)
 	if err != nil {
-		return createErrorResponse(fmt.Sprintf("Failed to marshal response: %v", err))
+		return createPlacementErrorResponse(err.Error(), api.RejectionReason(err))
 	}
 
+	response := api.NewPlacementResponse(scored, alphabet)
+	responseJSON, _ := json.Marshal(response)
+	return string(responseJSON)
+}
+
+// createPlacementErrorResponse creates an error response for placement scoring
+func createPlacementErrorResponse(error string, reason string) string {
+	response := api.PlacementResponse{Error: error, Reason: reason}
+	responseJSON, _ := json.Marshal(response)
 	return string(responseJSON)
 }
 
-// getGaddag loads or retrieves cached GADDAG
-func getGaddag(dictionary string) (*gaddag.GADDAG, error) {
+// getLayout returns the custom layout if one was sent, otherwise the named preset
+func getLayout(name string, custom *board.Layout) (*board.Layout, error) {
+	if custom != nil {
+		if err := custom.Validate(); err != nil {
+			return nil, err
+		}
+		return custom, nil
+	}
+	return board.LayoutByName(name)
+}
+
+// getGaddag loads or retrieves cached GADDAG, with words spelled in the
+// distribution's tiles. English dictionaries are loaded from the prebuilt
+// .gaddag file made by cmd/gaddagc when the server has one, otherwise the
+// GADDAG is built from the word list.
+func getGaddag(dictionary string, dist *game.LetterDistribution) (gaddag.Lexicon, error) {
 	// Normalise dictionary name to lowercase for consistency
 	dictLower := strings.ToLower(dictionary)
+	cacheKey := dictLower + "/" + dist.Name
 
-	if g, exists := gaddagCache[dictLower]; exists {
+	if g, exists := gaddagCache[cacheKey]; exists {
 		return g, nil
 	}
 
@@ -392,44 +328,118 @@ func getGaddag(dictionary string) (*gaddag.GADDAG, error) {
 	var filename string
 	switch dictLower {
 	case "csw24":
-		filename = "CSW24.txt"
+		filename = "CSW24"
 	case "nwl2023":
-		filename = "NWL2023.txt"
+		filename = "NWL2023"
 	default:
 		return nil, fmt.Errorf("unknown dictionary: %s", dictionary)
 	}
 
-	// Fetch dictionary via HTTP
-	url := "/dictionaries/" + filename
-
-	// Use XMLHttpRequest for synchronous fetch
-	xhr := js.Global().Get("XMLHttpRequest").New()
-	xhr.Call("open", "GET", url, false) // false = synchronous
-	xhr.Call("send")
+	// Prebuilt files are spelled in the English alphabet
+	if dist.Name == "english" {
+		if data, err := fetchBytes("/dictionaries/" + filename + ".gaddag"); err == nil {
+			if c, err := gaddag.ReadCompact(bytes.NewReader(data)); err == nil {
+				gaddagCache[cacheKey] = c
+				return c, nil
+			}
+		}
+	}
 
-	if xhr.Get("status").Int() != 200 {
-		return nil, fmt.Errorf("failed to fetch dictionary: status %d", xhr.Get("status").Int())
+	// Fetch dictionary via HTTP
+	text, err := fetchText("/dictionaries/" + filename + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dictionary: %w", err)
 	}
 
-	text := xhr.Get("responseText").String()
 	words := strings.Split(strings.TrimSpace(text), "\n")
 
-	// Build GADDAG
+	// Build GADDAG, skipping words the distribution cannot spell
 	g := gaddag.New()
 	for _, word := range words {
 		word = strings.TrimSpace(word)
-		if word != "" {
-			g.Add(strings.ToUpper(word)) // Ensure uppercase
+		if word == "" {
+			continue
+		}
+		if encoded, err := dist.Alphabet().Encode(word); err == nil {
+			g.Add(encoded)
+		}
+	}
+
+	// Keep only the compact form; the trie it was built from is much larger
+	c := g.Compact()
+	gaddagCache[cacheKey] = c
+	return c, nil
+}
+
+// getDistribution loads or retrieves a cached letter distribution. English is
+// built in; any other name is fetched from /distributions/<name>.txt
+func getDistribution(name string) (*game.LetterDistribution, error) {
+	nameLower := strings.ToLower(name)
+	if nameLower == "" || nameLower == "english" {
+		return game.EnglishDistribution(), nil
+	}
+
+	if dist, exists := distributionCache[nameLower]; exists {
+		return dist, nil
+	}
+
+	for _, c := range nameLower {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return nil, fmt.Errorf("invalid distribution name: %s", name)
 		}
 	}
 
-	gaddagCache[dictLower] = g
-	return g, nil
+	text, err := fetchText("/distributions/" + nameLower + ".txt")
+	if err != nil {
+		return nil, err
+	}
+
+	dist, err := game.ParseDistribution(nameLower, strings.NewReader(text))
+	if err != nil {
+		return nil, err
+	}
+
+	distributionCache[nameLower] = dist
+	return dist, nil
+}
+
+// fetchText downloads a file from the server using a synchronous XMLHttpRequest
+func fetchText(url string) (string, error) {
+	xhr := js.Global().Get("XMLHttpRequest").New()
+	xhr.Call("open", "GET", url, false) // false = synchronous
+	xhr.Call("send")
+
+	if status := xhr.Get("status").Int(); status != 200 {
+		return "", fmt.Errorf("GET %s: status %d", url, status)
+	}
+
+	return xhr.Get("responseText").String(), nil
+}
+
+// fetchBytes downloads a binary file using a synchronous XMLHttpRequest.
+// Synchronous requests cannot ask for an ArrayBuffer, so the response is
+// read as text with a charset that maps each byte to one character.
+func fetchBytes(url string) ([]byte, error) {
+	xhr := js.Global().Get("XMLHttpRequest").New()
+	xhr.Call("open", "GET", url, false) // false = synchronous
+	xhr.Call("overrideMimeType", "text/plain; charset=x-user-defined")
+	xhr.Call("send")
+
+	if status := xhr.Get("status").Int(); status != 200 {
+		return nil, fmt.Errorf("GET %s: status %d", url, status)
+	}
+
+	text := xhr.Get("responseText").String()
+	data := make([]byte, 0, len(text))
+	for _, ch := range text {
+		data = append(data, byte(ch))
+	}
+	return data, nil
 }
 
 // createErrorResponse creates an error response
 func createErrorResponse(error string) string {
-	response := AnalysisResponse{Error: error}
+	response := api.AnalysisResponse{Error: error}
 	responseJSON, _ := json.Marshal(response)
 	return string(responseJSON)
 }
@@ -438,6 +448,7 @@ func main() {
 	// Register the functions
 	js.Global().Set("analyzePosition", js.FuncOf(analyzePosition))
 	js.Global().Set("validateWords", js.FuncOf(validateWords))
+	js.Global().Set("scorePlacement", js.FuncOf(scorePlacement))
 
 	// Keep the program running
 	select {}
@@ -0,0 +1,132 @@
+// Package api holds the JSON requests and responses shared by the
+// WebAssembly bridge and the command line, so that the browser and the
+// terminal speak the same schema, along with the conversions between them
+// and the engine's types.
+package api
+
+import (
+	"tiletactics/backend/internal/board"
+)
+
+// ValidationRequest represents word validation input
+type ValidationRequest struct {
+	Words        []string `json:"words"`
+	Dictionary   string   `json:"dictionary"`
+	Distribution string   `json:"distribution,omitempty"`
+}
+
+// ValidationResponse represents word validation output
+type ValidationResponse struct {
+	Results      []WordValidation `json:"results"`
+	AllValid     bool             `json:"allValid"`
+	InvalidWords []string         `json:"invalidWords,omitempty"`
+	Error        string           `json:"error,omitempty"`
+}
+
+// WordValidation represents validation result for a single word
+type WordValidation struct {
+	Word    string `json:"word"`
+	IsValid bool   `json:"isValid"`
+}
+
+// AnalysisRequest asks for the best moves in a position
+type AnalysisRequest struct {
+	Board          [][]TileJSON   `json:"board"`
+	Rack           []TileJSON     `json:"rack"`
+	RemainingTiles map[string]int `json:"remainingTiles"`
+	Dictionary     string         `json:"dictionary"`
+	Layout         string         `json:"layout,omitempty"`       // "standard" (default) or "super"
+	CustomLayout   *board.Layout  `json:"customLayout,omitempty"` // Overrides Layout when set
+	Distribution   string         `json:"distribution,omitempty"` // "english" (default) or a file in /distributions/
+	BagSize        *int           `json:"bagSize,omitempty"`      // Tiles in the bag; when set, exchanges and passing are considered too
+	CGP            string         `json:"cgp,omitempty"`          // A position string; replaces board, rack and remainingTiles when set
+}
+
+// TileJSON represents a tile in JSON format
+type TileJSON struct {
+	Letter  string `json:"letter"`
+	Value   int    `json:"value"`
+	IsBlank bool   `json:"isBlank"`
+}
+
+// AnalysisResponse lists the best moves found, best first
+type AnalysisResponse struct {
+	Moves []MoveJSON `json:"moves"`
+	Error string     `json:"error,omitempty"`
+}
+
+// MoveJSON represents a move in JSON format
+type MoveJSON struct {
+	Kind        string           `json:"kind"`     // "place", "exchange" or "pass"
+	Notation    string           `json:"notation"` // Tournament notation, such as "8G Q(U)IXOTIc"
+	Word        string           `json:"word"`
+	Position    PositionJSON     `json:"position"`
+	Direction   string           `json:"direction"`
+	Score       int              `json:"score"`
+	TilesPlaced []PlacedTileJSON `json:"tilesPlaced"`
+	Exchanged   []TileJSON       `json:"exchanged,omitempty"`
+	Leave       []TileJSON       `json:"leave"`
+	Breakdown   *BreakdownJSON   `json:"breakdown,omitempty"`
+}
+
+// BreakdownJSON explains how a move's score was made up
+type BreakdownJSON struct {
+	Words []WordBreakdownJSON `json:"words"`
+	Bingo int                 `json:"bingo"`
+}
+
+// WordBreakdownJSON represents the score of one word formed by a move
+type WordBreakdownJSON struct {
+	Word           string        `json:"word"`
+	Premiums       []PremiumJSON `json:"premiums"`
+	WordMultiplier int           `json:"wordMultiplier"`
+	Score          int           `json:"score"`
+}
+
+// PremiumJSON represents a premium square used by a move
+type PremiumJSON struct {
+	Position   PositionJSON `json:"position"`
+	Type       string       `json:"type"` // "DL", "TL", "DW" or "TW"
+	Multiplier int          `json:"multiplier"`
+}
+
+// PositionJSON represents a position in JSON format
+type PositionJSON struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// PlacedTileJSON represents a placed tile in JSON format
+type PlacedTileJSON struct {
+	Position PositionJSON `json:"position"`
+	Tile     TileJSON     `json:"tile"`
+}
+
+// PlacementRequest represents tiles a player has placed on the board this turn
+type PlacementRequest struct {
+	Board        [][]TileJSON     `json:"board"` // Board before the placement
+	Tiles        []PlacedTileJSON `json:"tiles"`
+	Move         string           `json:"move,omitempty"` // A move such as "8G Q(U)IXOTIc", used instead of tiles
+	Dictionary   string           `json:"dictionary"`
+	Layout       string           `json:"layout,omitempty"`
+	CustomLayout *board.Layout    `json:"customLayout,omitempty"`
+	Distribution string           `json:"distribution,omitempty"`
+}
+
+// PlacementResponse is a scored placement with every word it forms
+type PlacementResponse struct {
+	Move         *MoveJSON       `json:"move,omitempty"`
+	Words        []WordScoreJSON `json:"words"`
+	Bingo        int             `json:"bingo"`
+	AllValid     bool            `json:"allValid"`
+	InvalidWords []string        `json:"invalidWords,omitempty"`
+	Reason       string          `json:"reason,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// WordScoreJSON represents the score of one word formed by a placement
+type WordScoreJSON struct {
+	Word    string `json:"word"`
+	Score   int    `json:"score"`
+	IsValid bool   `json:"isValid"`
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/validator"
+)
+
+func newTestLexicon() gaddag.Lexicon {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "CATS", "AT", "AS", "TA", "SCAT", "ACT"} {
+		g.Add(word)
+	}
+	return g
+}
+
+func TestValidateWords(t *testing.T) {
+	dist := game.EnglishDistribution()
+	response := ValidateWords(newTestLexicon(), dist.Alphabet(), []string{"CAT", "caT", "TAC", ""})
+
+	want := []bool{true, true, false, false}
+	for i, result := range response.Results {
+		if result.IsValid != want[i] {
+			t.Errorf("%q valid = %v, want %v", result.Word, result.IsValid, want[i])
+		}
+	}
+	if response.AllValid || len(response.InvalidWords) != 2 || response.InvalidWords[0] != "TAC" {
+		t.Errorf("allValid %v, invalid words %q", response.AllValid, response.InvalidWords)
+	}
+}
+
+func TestNewPlacementResponse(t *testing.T) {
+	dist := game.EnglishDistribution()
+	b := board.New()
+	for i, letter := range "CAT" {
+		b.SetTile(7, 7+i, &game.Tile{Letter: letter, Value: dist.Value(letter)})
+	}
+
+	// S makes CATS, and TS down from the T, which is not a word
+	placed := []game.PlacedTile{
+		{Position: game.Position{Row: 7, Col: 10}, Tile: dist.Tile('S')},
+		{Position: game.Position{Row: 8, Col: 10}, Tile: dist.Tile('T')},
+	}
+	scored, err := validator.New(newTestLexicon()).ScorePlacement(b, placed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := NewPlacementResponse(scored, dist.Alphabet())
+	if response.AllValid || response.Reason != "phonyWord" {
+		t.Errorf("allValid %v, reason %q, want a phony word", response.AllValid, response.Reason)
+	}
+	valid := make(map[string]bool)
+	for _, word := range response.Words {
+		valid[word.Word] = word.IsValid
+	}
+	if len(valid) != 2 || !valid["CATS"] || valid["ST"] {
+		t.Errorf("words = %+v, want CATS valid and ST not", response.Words)
+	}
+	if response.Move == nil || response.Move.Score != scored.Move.Score {
+		t.Errorf("move = %+v, want score %d", response.Move, scored.Move.Score)
+	}
+}
+
+func TestBestMoves(t *testing.T) {
+	dist := game.EnglishDistribution()
+	b := board.New()
+	rack := []game.Tile{dist.Tile('C'), dist.Tile('A'), dist.Tile('T'), dist.Tile('S')}
+	unseen := make(map[rune]int)
+	for letter, count := range dist.Counts {
+		unseen[letter] = count
+	}
+	for _, tile := range rack {
+		unseen[tile.Letter]--
+	}
+
+	bagSize := 86
+	moves := BestMoves(newTestLexicon(), b, rack, unseen, dist, &bagSize, 5)
+	if len(moves) != 5 {
+		t.Fatalf("got %d moves, want 5", len(moves))
+	}
+	for _, move := range moves {
+		if move.Kind == game.MovePlace && len(move.Breakdown.Words) == 0 {
+			t.Errorf("%s has no score breakdown", move.Notation(dist.Alphabet()))
+		}
+	}
+
+	// The response is the JSON the browser reads
+	data, err := json.Marshal(NewAnalysisResponse(moves, dist.Alphabet()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"notation":"`+moves[0].Notation(dist.Alphabet())+`"`) {
+		t.Errorf("response %s does not name the best move", data)
+	}
+}
+
+func TestPlacementIgnoresSentValues(t *testing.T) {
+	dist := game.EnglishDistribution()
+	b := board.New()
+
+	// The request claims both tiles are worth 10; AT scores 2 on the centre
+	var placed []game.PlacedTile
+	for i, letter := range []string{"A", "T"} {
+		tile, err := TileFromJSON(TileJSON{Letter: letter, Value: 10}, dist.Alphabet())
+		if err != nil {
+			t.Fatal(err)
+		}
+		placed = append(placed, game.PlacedTile{Position: game.Position{Row: 7, Col: 7 + i}, Tile: tile})
+	}
+	scored, err := validator.NewWithDistribution(newTestLexicon(), dist).ScorePlacement(b, placed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := NewPlacementResponse(scored, dist.Alphabet()); response.Move.Score != 4 {
+		t.Errorf("score = %d, want 4", response.Move.Score)
+	}
+}
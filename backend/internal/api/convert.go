@@ -0,0 +1,179 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/validator"
+)
+
+// RejectionReason maps a validator error to a stable code callers can switch on
+func RejectionReason(err error) string {
+	reasons := []struct {
+		err  error
+		code string
+	}{
+		{validator.ErrNoTilesPlaced, "noTilesPlaced"},
+		{validator.ErrOffBoard, "offBoard"},
+		{validator.ErrSquareOccupied, "squareOccupied"},
+		{validator.ErrNotCollinear, "notCollinear"},
+		{validator.ErrGap, "gap"},
+		{validator.ErrMissingCenter, "missingCenter"},
+		{validator.ErrNotConnected, "notConnected"},
+		{validator.ErrNoWordFormed, "noWordFormed"},
+		{validator.ErrTileNotOnRack, "tileNotOnRack"},
+		{validator.ErrPhonyWord, "phonyWord"},
+	}
+
+	for _, r := range reasons {
+		if errors.Is(err, r.err) {
+			return r.code
+		}
+	}
+	return ""
+}
+
+// MoveToJSON converts a move to its JSON form, spelling tiles as the alphabet displays them
+func MoveToJSON(move game.Move, alphabet *game.Alphabet) MoveJSON {
+	moveJSON := MoveJSON{
+		Notation: move.Notation(alphabet),
+		Word:     alphabet.Decode(move.Word),
+		Position: PositionJSON{Row: move.Position.Row, Col: move.Position.Col},
+		Score:    move.Score,
+	}
+
+	// Set kind, and direction for placements
+	switch move.Kind {
+	case game.MoveExchange:
+		moveJSON.Kind = "exchange"
+	case game.MovePass:
+		moveJSON.Kind = "pass"
+	default:
+		moveJSON.Kind = "place"
+		if move.Direction == game.Horizontal {
+			moveJSON.Direction = "H"
+		} else {
+			moveJSON.Direction = "V"
+		}
+	}
+
+	// Convert exchanged tiles; blanks go back to the bag undesignated
+	for _, tile := range move.Exchanged {
+		letter := alphabet.Display(tile.Letter)
+		if tile.IsBlank {
+			letter = "?"
+		}
+		moveJSON.Exchanged = append(moveJSON.Exchanged, TileJSON{
+			Letter:  letter,
+			Value:   tile.Value,
+			IsBlank: tile.IsBlank,
+		})
+	}
+
+	// Convert tiles placed
+	moveJSON.TilesPlaced = make([]PlacedTileJSON, len(move.TilesPlaced))
+	for j, placed := range move.TilesPlaced {
+		// Keep the letter even for blank tiles
+		// Blank tiles should have their designated letter (what they represent)
+		letter := alphabet.Display(placed.Tile.Letter)
+
+		// Only set to empty if there's truly no letter (which shouldn't happen in valid moves)
+		if placed.Tile.Letter == 0 {
+			letter = ""
+		}
+
+		moveJSON.TilesPlaced[j] = PlacedTileJSON{
+			Position: PositionJSON{Row: placed.Position.Row, Col: placed.Position.Col},
+			Tile: TileJSON{
+				Letter:  letter, // Keeps the letter for blanks
+				Value:   placed.Tile.Value,
+				IsBlank: placed.Tile.IsBlank,
+			},
+		}
+	}
+
+	// Convert leave
+	moveJSON.Leave = make([]TileJSON, len(move.Leave))
+	for j, tile := range move.Leave {
+		letter := alphabet.Display(tile.Letter)
+
+		// For leave tiles, blanks might be represented as '?'
+		// Keep the letter as-is unless it's truly empty
+		if tile.Letter == 0 {
+			letter = ""
+		} else if tile.IsBlank && tile.Letter == '?' {
+			// Leave blanks as '?' in the leave
+			letter = "?"
+		}
+
+		moveJSON.Leave[j] = TileJSON{
+			Letter:  letter,
+			Value:   tile.Value,
+			IsBlank: tile.IsBlank,
+		}
+	}
+
+	// Convert score breakdown when one was worked out
+	if len(move.Breakdown.Words) > 0 {
+		moveJSON.Breakdown = &BreakdownJSON{
+			Words: make([]WordBreakdownJSON, len(move.Breakdown.Words)),
+			Bingo: move.Breakdown.Bingo,
+		}
+		for j, word := range move.Breakdown.Words {
+			premiums := make([]PremiumJSON, len(word.Premiums))
+			for k, premium := range word.Premiums {
+				premiums[k] = PremiumJSON{
+					Position:   PositionJSON{Row: premium.Position.Row, Col: premium.Position.Col},
+					Type:       premium.Label(),
+					Multiplier: premium.Multiplier,
+				}
+			}
+			moveJSON.Breakdown.Words[j] = WordBreakdownJSON{
+				Word:           alphabet.Decode(word.Word),
+				Premiums:       premiums,
+				WordMultiplier: word.WordMultiplier,
+				Score:          word.Score,
+			}
+		}
+	}
+
+	return moveJSON
+}
+
+// BoardFromJSON converts a board grid from JSON, leaving squares with no letter empty
+func BoardFromJSON(grid [][]TileJSON, layout *board.Layout, alphabet *game.Alphabet) (*board.Board, error) {
+	b := board.NewWithLayout(layout)
+	for row := 0; row < len(grid); row++ {
+		for col := 0; col < len(grid[row]); col++ {
+			tileJSON := grid[row][col]
+			if tileJSON.Letter != "" {
+				tile, err := TileFromJSON(tileJSON, alphabet)
+				if err != nil {
+					return nil, fmt.Errorf("square (%d,%d): %w", row, col, err)
+				}
+				b.SetTile(row, col, &tile)
+			}
+		}
+	}
+	return b, nil
+}
+
+// TileFromJSON converts a tile whose letter is a display string such as "A",
+// "Ñ" or "CH" into a tile code from the alphabet
+func TileFromJSON(tileJSON TileJSON, alphabet *game.Alphabet) (game.Tile, error) {
+	if tileJSON.IsBlank && tileJSON.Letter == "?" {
+		return game.Tile{Letter: game.BlankLetter, Value: 0, IsBlank: true}, nil
+	}
+
+	code, ok := alphabet.Code(tileJSON.Letter)
+	if !ok {
+		return game.Tile{}, fmt.Errorf("unknown tile %q", tileJSON.Letter)
+	}
+
+	return game.Tile{
+		Letter:  code,
+		Value:   tileJSON.Value,
+		IsBlank: tileJSON.IsBlank,
+	}, nil
+}
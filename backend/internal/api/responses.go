@@ -0,0 +1,137 @@
+package api
+
+import (
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/evaluator"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+	"tiletactics/backend/internal/scorer"
+	"tiletactics/backend/internal/validator"
+)
+
+// DefaultReplySamples is how many opponent racks BestMoves estimates each
+// leading move's best reply with; few enough to keep analysis interactive
+const DefaultReplySamples = 4
+
+// BestMoves generates every placement from rack, evaluates each as it is
+// found and returns the best n, with score breakdowns for the placements.
+// When bagSize is set, exchanges and passing are considered too. The leaders
+// are ranked again with the opponent's expected best reply.
+func BestMoves(lexicon gaddag.Lexicon, b *board.Board, rack []game.Tile, remaining map[rune]int, dist *game.LetterDistribution, bagSize *int, n int) []game.Move {
+	gen := generator.NewWithDistribution(lexicon, b, dist)
+	eval := evaluator.NewWithDistribution(remaining, evaluator.DefaultWeights, dist)
+	eval.SetBoard(b, lexicon)
+	eval.SetReplySamples(DefaultReplySamples)
+
+	// Without a bag size there are no exchanges; one more move is asked for
+	// so that dropping the pass still leaves n
+	bagLen := 0
+	if bagSize != nil {
+		bagLen = *bagSize
+	}
+	var bestMoves []game.Move
+	for _, ranked := range eval.BestMoves(gen, rack, bagLen, n+1) {
+		if ranked.Move.Kind == game.MovePass && bagSize == nil {
+			continue
+		}
+		bestMoves = append(bestMoves, ranked.Move)
+	}
+	bestMoves = bestMoves[:min(n, len(bestMoves))]
+
+	// Explain the scores of the moves we return
+	sc := scorer.NewWithDistribution(b, dist)
+	for i := range bestMoves {
+		if bestMoves[i].Kind == game.MovePlace {
+			bestMoves[i].Breakdown = sc.Breakdown(bestMoves[i])
+		}
+	}
+	return bestMoves
+}
+
+// NewAnalysisResponse converts moves to their JSON form
+func NewAnalysisResponse(moves []game.Move, alphabet *game.Alphabet) AnalysisResponse {
+	response := AnalysisResponse{
+		Moves: make([]MoveJSON, len(moves)),
+	}
+	for i, move := range moves {
+		response.Moves[i] = MoveToJSON(move, alphabet)
+	}
+	return response
+}
+
+// ValidateWords checks words against a lexicon. Words are written as the
+// alphabet displays them; lower-case letters for blanks are accepted.
+func ValidateWords(lexicon gaddag.Lexicon, alphabet *game.Alphabet, words []string) ValidationResponse {
+	results := make([]WordValidation, len(words))
+	invalidWords := []string{}
+	allValid := true
+
+	for i, word := range words {
+		// Skip empty words
+		if word == "" {
+			results[i] = WordValidation{
+				Word:    word,
+				IsValid: false,
+			}
+			allValid = false
+			invalidWords = append(invalidWords, word)
+			continue
+		}
+
+		// Blanks are sent in lowercase; encoding uppercases them and turns
+		// digraphs into single tiles
+		checkWord, err := alphabet.Encode(word)
+
+		// Check if word exists in dictionary
+		isValid := err == nil && lexicon.Contains(checkWord)
+
+		results[i] = WordValidation{
+			Word:    word,
+			IsValid: isValid,
+		}
+
+		if !isValid {
+			allValid = false
+			invalidWords = append(invalidWords, word)
+		}
+	}
+
+	return ValidationResponse{
+		Results:      results,
+		AllValid:     allValid,
+		InvalidWords: invalidWords,
+	}
+}
+
+// NewPlacementResponse converts a scored placement to its JSON form, marking
+// each word formed as valid or not
+func NewPlacementResponse(scored validator.ScoredPlacement, alphabet *game.Alphabet) PlacementResponse {
+	phony := make(map[string]bool)
+	for _, word := range scored.PhonyWords {
+		phony[word] = true
+	}
+
+	moveJSON := MoveToJSON(scored.Move, alphabet)
+	response := PlacementResponse{
+		Move:         &moveJSON,
+		Words:        make([]WordScoreJSON, len(scored.Move.Breakdown.Words)),
+		Bingo:        scored.Move.Breakdown.Bingo,
+		AllValid:     len(scored.PhonyWords) == 0,
+		InvalidWords: make([]string, len(scored.PhonyWords)),
+	}
+	for i, word := range scored.PhonyWords {
+		response.InvalidWords[i] = alphabet.Decode(word)
+	}
+	for i, word := range scored.Move.Breakdown.Words {
+		response.Words[i] = WordScoreJSON{
+			Word:    alphabet.Decode(word.Word),
+			Score:   word.Score,
+			IsValid: !phony[word.Word],
+		}
+	}
+	if !response.AllValid {
+		response.Reason = RejectionReason(validator.ErrPhonyWord)
+	}
+	return response
+}
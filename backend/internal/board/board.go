@@ -5,8 +5,9 @@ import (
 )
 
 type Board struct {
-	tiles       [game.BoardSize][game.BoardSize]*game.Tile
-	multipliers [game.BoardSize][game.BoardSize]Multiplier
+	layout      *Layout
+	tiles       [][]*game.Tile
+	multipliers [][]Multiplier
 }
 
 type Multiplier struct {
@@ -22,34 +23,127 @@ const (
 	TripleLetter
 	DoubleWord
 	TripleWord
+	QuadrupleLetter
+	QuadrupleWord
 )
 
+// IsWord returns true if the multiplier applies to the whole word
+func (m Multiplier) IsWord() bool {
+	return m.Type == DoubleWord || m.Type == TripleWord || m.Type == QuadrupleWord
+}
+
+// LetterMultiplier returns the factor applied to a tile placed on this square
+func (m Multiplier) LetterMultiplier() int {
+	if m.Type == None || m.IsWord() {
+		return 1
+	}
+	return m.Value
+}
+
+// WordMultiplier returns the factor applied to a word covering this square
+func (m Multiplier) WordMultiplier() int {
+	if !m.IsWord() {
+		return 1
+	}
+	return m.Value
+}
+
+// New creates an empty standard 15x15 board
 func New() *Board {
-	b := &Board{}
-	b.initializeMultipliers()
+	return NewWithLayout(StandardLayout())
+}
+
+// NewWithLayout creates an empty board with the given layout
+func NewWithLayout(layout *Layout) *Board {
+	b := &Board{
+		layout:      layout,
+		tiles:       make([][]*game.Tile, layout.Size),
+		multipliers: make([][]Multiplier, layout.Size),
+	}
+	for row := 0; row < layout.Size; row++ {
+		b.tiles[row] = make([]*game.Tile, layout.Size)
+		b.multipliers[row] = make([]Multiplier, layout.Size)
+		for col := 0; col < layout.Size; col++ {
+			b.multipliers[row][col] = layout.Multiplier(row, col)
+		}
+	}
 	return b
 }
 
+// Clone returns a copy of the board that can be changed independently
+func (b *Board) Clone() *Board {
+	c := &Board{
+		layout:      b.layout,
+		tiles:       make([][]*game.Tile, len(b.tiles)),
+		multipliers: b.multipliers, // Never changed after creation
+	}
+	for row := range b.tiles {
+		c.tiles[row] = append([]*game.Tile(nil), b.tiles[row]...)
+	}
+	return c
+}
+
+// Layout returns the layout the board was created with
+func (b *Board) Layout() *Layout {
+	return b.layout
+}
+
+// Size returns the number of rows (and columns) on the board
+func (b *Board) Size() int {
+	return b.layout.Size
+}
+
+// Center returns the square the first move must cover
+func (b *Board) Center() game.Position {
+	return b.layout.Start
+}
+
+// InBounds returns true if the square is on the board
+func (b *Board) InBounds(row, col int) bool {
+	return row >= 0 && row < b.layout.Size && col >= 0 && col < b.layout.Size
+}
+
 func (b *Board) GetTile(row, col int) *game.Tile {
-	if row < 0 || row >= game.BoardSize || col < 0 || col >= game.BoardSize {
+	if !b.InBounds(row, col) {
 		return nil
 	}
 	return b.tiles[row][col]
 }
 
 func (b *Board) SetTile(row, col int, tile *game.Tile) {
-	if row >= 0 && row < game.BoardSize && col >= 0 && col < game.BoardSize {
+	if b.InBounds(row, col) {
 		b.tiles[row][col] = tile
 	}
 }
 
+// Place puts a placement's tiles on the board; other moves leave it alone
+func (b *Board) Place(move game.Move) {
+	if move.Kind != game.MovePlace {
+		return
+	}
+	for _, placed := range move.TilesPlaced {
+		tile := placed.Tile
+		b.SetTile(placed.Position.Row, placed.Position.Col, &tile)
+	}
+}
+
+// Unplace takes a placement's tiles off the board, undoing Place
+func (b *Board) Unplace(move game.Move) {
+	if move.Kind != game.MovePlace {
+		return
+	}
+	for _, placed := range move.TilesPlaced {
+		b.SetTile(placed.Position.Row, placed.Position.Col, nil)
+	}
+}
+
 func (b *Board) IsEmpty(row, col int) bool {
 	return b.GetTile(row, col) == nil
 }
 
 func (b *Board) IsAnchor(row, col int) bool {
 	// Center square is anchor for first move
-	if b.IsCompletelyEmpty() && row == 7 && col == 7 {
+	if b.IsCompletelyEmpty() && row == b.layout.Start.Row && col == b.layout.Start.Col {
 		return true
 	}
 
@@ -70,8 +164,8 @@ func (b *Board) IsAnchor(row, col int) bool {
 
 // IsCompletelyEmpty returns true if the board has no tiles placed
 func (b *Board) IsCompletelyEmpty() bool {
-	for row := 0; row < game.BoardSize; row++ {
-		for col := 0; col < game.BoardSize; col++ {
+	for row := 0; row < b.layout.Size; row++ {
+		for col := 0; col < b.layout.Size; col++ {
 			if b.tiles[row][col] != nil {
 				return false
 			}
@@ -82,7 +176,7 @@ func (b *Board) IsCompletelyEmpty() bool {
 
 // GetMultiplier returns the multiplier at the given position
 func (b *Board) GetMultiplier(row, col int) Multiplier {
-	if row < 0 || row >= game.BoardSize || col < 0 || col >= game.BoardSize {
+	if !b.InBounds(row, col) {
 		return Multiplier{Type: None, Value: 1}
 	}
 	return b.multipliers[row][col]
@@ -18,6 +18,43 @@ func TestNewBoard(t *testing.T) {
 	}
 }
 
+func TestClone(t *testing.T) {
+	b := New()
+	b.SetTile(7, 7, &game.Tile{Letter: 'A', Value: 1})
+
+	c := b.Clone()
+	c.SetTile(7, 8, &game.Tile{Letter: 'T', Value: 1})
+
+	if c.GetTile(7, 7) == nil || c.GetTile(7, 8) == nil {
+		t.Error("clone should keep the original tiles and take new ones")
+	}
+	if b.GetTile(7, 8) != nil {
+		t.Error("changing the clone should leave the original alone")
+	}
+}
+
+func TestPlace(t *testing.T) {
+	b := New()
+	move := game.Move{TilesPlaced: []game.PlacedTile{
+		{Position: game.Position{Row: 7, Col: 7}, Tile: game.Tile{Letter: 'A', Value: 1}},
+		{Position: game.Position{Row: 7, Col: 8}, Tile: game.Tile{Letter: 'T', Value: 1}},
+	}}
+
+	b.Place(move)
+	if tile := b.GetTile(7, 8); tile == nil || tile.Letter != 'T' {
+		t.Fatalf("(7,8) = %+v after Place, want T", tile)
+	}
+	b.Unplace(move)
+	if !b.IsCompletelyEmpty() {
+		t.Error("board not empty after Unplace")
+	}
+
+	b.Place(game.Move{Kind: game.MovePass})
+	if !b.IsCompletelyEmpty() {
+		t.Error("a pass put tiles on the board")
+	}
+}
+
 func TestIsAnchor(t *testing.T) {
 	b := New()
 
@@ -38,3 +75,85 @@ func TestIsAnchor(t *testing.T) {
 		t.Error("Squares adjacent to tiles should be anchors")
 	}
 }
+
+func TestBuiltInLayouts(t *testing.T) {
+	for _, name := range []string{"standard", "super"} {
+		layout, err := LayoutByName(name)
+		if err != nil {
+			t.Fatalf("LayoutByName(%q) error = %v", name, err)
+		}
+		if err := layout.Validate(); err != nil {
+			t.Errorf("%s layout is invalid: %v", name, err)
+		}
+
+		// Every built-in layout is symmetric about both diagonals
+		for row := 0; row < layout.Size; row++ {
+			for col := 0; col < layout.Size; col++ {
+				m := layout.Multiplier(row, col)
+				if other := layout.Multiplier(col, row); other != m {
+					t.Errorf("%s layout not symmetric at (%d,%d)", name, row, col)
+				}
+				if other := layout.Multiplier(layout.Size-1-row, layout.Size-1-col); other != m {
+					t.Errorf("%s layout not symmetric at (%d,%d)", name, row, col)
+				}
+			}
+		}
+	}
+
+	if _, err := LayoutByName("hexagonal"); err == nil {
+		t.Error("LayoutByName should reject unknown layouts")
+	}
+}
+
+func TestSuperBoard(t *testing.T) {
+	b := NewWithLayout(SuperLayout())
+
+	if b.Size() != 21 {
+		t.Errorf("Size() = %d, want 21", b.Size())
+	}
+	if !b.IsAnchor(10, 10) || b.IsAnchor(7, 7) {
+		t.Error("Only the super board's center should be an anchor on an empty board")
+	}
+	if m := b.GetMultiplier(0, 0); m.Type != QuadrupleWord || m.WordMultiplier() != 4 {
+		t.Errorf("GetMultiplier(0,0) = %+v, want quadruple word", m)
+	}
+	if m := b.GetMultiplier(1, 8); m.Type != QuadrupleLetter || m.LetterMultiplier() != 4 {
+		t.Errorf("GetMultiplier(1,8) = %+v, want quadruple letter", m)
+	}
+
+	b.SetTile(20, 20, &game.Tile{Letter: 'A', Value: 1})
+	if b.GetTile(20, 20) == nil {
+		t.Error("Should be able to place a tile in the super board's far corner")
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	layout, err := ParseLayout([]byte(`{
+		"name": "tiny",
+		"size": 3,
+		"start": {"row": 1, "col": 1},
+		"rows": ["T.T", ".D.", "T.T"]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+
+	b := NewWithLayout(layout)
+	if b.Center() != (game.Position{Row: 1, Col: 1}) {
+		t.Errorf("Center() = %v, want (1,1)", b.Center())
+	}
+	if m := b.GetMultiplier(2, 2); m.Type != TripleWord {
+		t.Errorf("GetMultiplier(2,2) = %+v, want triple word", m)
+	}
+
+	bad := []string{
+		`{"name": "short", "size": 3, "rows": ["...", "..."]}`,
+		`{"name": "unknown", "size": 1, "rows": ["x"]}`,
+		`{"name": "offboard", "size": 1, "start": {"row": 2, "col": 0}, "rows": ["."]}`,
+	}
+	for _, data := range bad {
+		if _, err := ParseLayout([]byte(data)); err == nil {
+			t.Errorf("ParseLayout(%s) should fail", data)
+		}
+	}
+}
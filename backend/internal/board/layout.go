@@ -0,0 +1,106 @@
+package board
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"tiletactics/backend/internal/game"
+)
+
+// Layout describes the shape of a board: its size, premium squares and the
+// square the first move must cover.
+//
+// Premium squares are given as one string per row, one character per square:
+//
+//	.  no premium      d  double letter   D  double word
+//	                   t  triple letter   T  triple word
+//	                   q  quadruple letter Q  quadruple word
+type Layout struct {
+	Name  string        `json:"name"`
+	Size  int           `json:"size"`
+	Start game.Position `json:"start"`
+	Rows  []string      `json:"rows"`
+}
+
+// premiumSquares maps layout characters to the multiplier they represent
+var premiumSquares = map[byte]Multiplier{
+	'.': {None, 1},
+	'd': {DoubleLetter, 2},
+	't': {TripleLetter, 3},
+	'q': {QuadrupleLetter, 4},
+	'D': {DoubleWord, 2},
+	'T': {TripleWord, 3},
+	'Q': {QuadrupleWord, 4},
+}
+
+// ParseLayout reads a layout from JSON and checks that it is well formed
+func ParseLayout(data []byte) (*Layout, error) {
+	var layout Layout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("failed to parse layout: %w", err)
+	}
+
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &layout, nil
+}
+
+// LoadLayout reads a custom layout from a JSON file
+func LoadLayout(filename string) (*Layout, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open layout file: %w", err)
+	}
+	return ParseLayout(data)
+}
+
+// LayoutByName returns one of the built-in layouts: "standard" or "super"
+func LayoutByName(name string) (*Layout, error) {
+	switch strings.ToLower(name) {
+	case "", "standard":
+		return StandardLayout(), nil
+	case "super":
+		return SuperLayout(), nil
+	default:
+		return nil, fmt.Errorf("unknown board layout: %s", name)
+	}
+}
+
+// Validate checks that the rows match the size, use only known premium
+// characters and that the start square is on the board
+func (l *Layout) Validate() error {
+	if l.Size <= 0 {
+		return fmt.Errorf("layout %q: size must be positive", l.Name)
+	}
+	if len(l.Rows) != l.Size {
+		return fmt.Errorf("layout %q: has %d rows, want %d", l.Name, len(l.Rows), l.Size)
+	}
+
+	for i, row := range l.Rows {
+		if len(row) != l.Size {
+			return fmt.Errorf("layout %q: row %d has %d squares, want %d", l.Name, i, len(row), l.Size)
+		}
+		for j := 0; j < len(row); j++ {
+			if _, ok := premiumSquares[row[j]]; !ok {
+				return fmt.Errorf("layout %q: unknown premium %q at (%d,%d)", l.Name, row[j], i, j)
+			}
+		}
+	}
+
+	if l.Start.Row < 0 || l.Start.Row >= l.Size || l.Start.Col < 0 || l.Start.Col >= l.Size {
+		return fmt.Errorf("layout %q: start square (%d,%d) is off the board", l.Name, l.Start.Row, l.Start.Col)
+	}
+
+	return nil
+}
+
+// Multiplier returns the premium at a square of the layout
+func (l *Layout) Multiplier(row, col int) Multiplier {
+	if row < 0 || row >= l.Size || col < 0 || col >= l.Size {
+		return Multiplier{Type: None, Value: 1}
+	}
+	return premiumSquares[l.Rows[row][col]]
+}
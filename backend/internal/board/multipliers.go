@@ -1,52 +1,61 @@
 package board
 
-func (b *Board) initializeMultipliers() {
-	// Triple word scores (red squares)
-	tripleWords := [][2]int{
-		{0, 0}, {0, 7}, {0, 14},
-		{7, 0}, {7, 14},
-		{14, 0}, {14, 7}, {14, 14},
-	}
-	for _, pos := range tripleWords {
-		b.multipliers[pos[0]][pos[1]] = Multiplier{TripleWord, 3}
-	}
-
-	// Double word scores (pink squares)
-	doubleWords := [][2]int{
-		{1, 1}, {2, 2}, {3, 3}, {4, 4},
-		{1, 13}, {2, 12}, {3, 11}, {4, 10},
-		{13, 1}, {12, 2}, {11, 3}, {10, 4},
-		{13, 13}, {12, 12}, {11, 11}, {10, 10},
-		{7, 7}, // Center star
-	}
-	for _, pos := range doubleWords {
-		b.multipliers[pos[0]][pos[1]] = Multiplier{DoubleWord, 2}
-	}
+import "tiletactics/backend/internal/game"
 
-	// Triple letter scores (dark blue squares)
-	tripleLetters := [][2]int{
-		{1, 5}, {1, 9},
-		{5, 1}, {5, 5}, {5, 9}, {5, 13},
-		{9, 1}, {9, 5}, {9, 9}, {9, 13},
-		{13, 5}, {13, 9},
-	}
-	for _, pos := range tripleLetters {
-		b.multipliers[pos[0]][pos[1]] = Multiplier{TripleLetter, 3}
+// StandardLayout returns the standard 15x15 board
+func StandardLayout() *Layout {
+	return &Layout{
+		Name:  "standard",
+		Size:  game.BoardSize,
+		Start: game.Position{Row: 7, Col: 7},
+		Rows: []string{
+			"T..d...T...d..T",
+			".D...t...t...D.",
+			"..D...d.d...D..",
+			"d..D...d...D..d",
+			"....D.....D....",
+			".t...t...t...t.",
+			"..d...d.d...d..",
+			"T..d...D...d..T", // Center star
+			"..d...d.d...d..",
+			".t...t...t...t.",
+			"....D.....D....",
+			"d..D...d...D..d",
+			"..D...d.d...D..",
+			".D...t...t...D.",
+			"T..d...T...d..T",
+		},
 	}
+}
 
-	// Double letter scores (light blue squares)
-	doubleLetters := [][2]int{
-		{0, 3}, {0, 11},
-		{2, 6}, {2, 8},
-		{3, 0}, {3, 7}, {3, 14},
-		{6, 2}, {6, 6}, {6, 8}, {6, 12},
-		{7, 3}, {7, 11},
-		{8, 2}, {8, 6}, {8, 8}, {8, 12},
-		{11, 0}, {11, 7}, {11, 14},
-		{12, 6}, {12, 8},
-		{14, 3}, {14, 11},
-	}
-	for _, pos := range doubleLetters {
-		b.multipliers[pos[0]][pos[1]] = Multiplier{DoubleLetter, 2}
+// SuperLayout returns the 21x21 Super board with quadruple premiums
+func SuperLayout() *Layout {
+	return &Layout{
+		Name:  "super",
+		Size:  21,
+		Start: game.Position{Row: 10, Col: 10},
+		Rows: []string{
+			"Q..d...T..d..T...d..Q",
+			".D..t...q...q...t..D.",
+			"..D..q...d.d...q..D..",
+			"d..D...d..d..d...D..d",
+			".t..D...t...t...D..t.",
+			"..q..D...d.d...D..q..",
+			"......D.......D......",
+			"T..d...t..d..t...d..T",
+			".q..t...d...d...t..q.",
+			"..d..d...d.d...d..d..",
+			"d..d...d..D..d...d..d", // Center star
+			"..d..d...d.d...d..d..",
+			".q..t...d...d...t..q.",
+			"T..d...t..d..t...d..T",
+			"......D.......D......",
+			"..q..D...d.d...D..q..",
+			".t..D...t...t...D..t.",
+			"d..D...d..d..d...D..d",
+			"..D..q...d.d...q..D..",
+			".D..t...q...q...t..D.",
+			"Q..d...T..d..T...d..Q",
+		},
 	}
 }
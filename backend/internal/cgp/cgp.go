@@ -0,0 +1,355 @@
+// Package cgp reads and writes single positions as CGP strings, the
+// compact position format used by Macondo and other analysis tools:
+//
+//	15/15/15/15/15/15/15/7CAT5/15/15/15/15/15/15/15 AEINRS?/ 6/0 0 lex NWL2023;
+//
+// The board comes first, one row per '/', with digits counting empty
+// squares, lower-case letters for blanks and tiles of more than one
+// character in brackets, such as [CH]. Then come the racks of the player
+// to move and their opponent, their scores, the number of scoreless turns
+// in a row and any options, each ended by ';'.
+package cgp
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/game"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RackSize is how many tiles a full rack holds
+const RackSize = 7
+
+// Option is one option after the zero-turn count, such as "lex NWL2023"
+type Option struct {
+	Name  string
+	Value string
+}
+
+// Position is a board with the racks and scores around it
+type Position struct {
+	Board     *board.Board
+	Racks     [2][]game.Tile // The player to move first; an unknown rack is empty
+	Scores    [2]int         // The player to move first
+	ZeroTurns int            // Scoreless turns in a row before this one
+	Lexicon   string         // From the lex option
+	Options   []Option       // Other options, in order
+
+	dist *game.LetterDistribution
+}
+
+// New creates a position on a board with English tiles, with rack for the
+// player to move
+func New(b *board.Board, rack []game.Tile) *Position {
+	return NewWithDistribution(b, rack, game.EnglishDistribution())
+}
+
+// NewWithDistribution creates a position on a board played with the given
+// letter distribution, with rack for the player to move
+func NewWithDistribution(b *board.Board, rack []game.Tile, dist *game.LetterDistribution) *Position {
+	return &Position{Board: b, Racks: [2][]game.Tile{rack, nil}, dist: dist}
+}
+
+// Parse reads a CGP string for a standard board with English tiles
+func Parse(s string) (*Position, error) {
+	return ParseWithSetup(s, board.StandardLayout(), game.EnglishDistribution())
+}
+
+// ParseWithSetup reads a CGP string for a board of the given layout played
+// with the given letter distribution. It fails if the position holds more
+// of a tile than the distribution has.
+func ParseWithSetup(s string, layout *board.Layout, dist *game.LetterDistribution) (*Position, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("cgp needs a board, racks, scores and a zero-turn count")
+	}
+
+	b, err := parseBoard(fields[0], layout, dist)
+	if err != nil {
+		return nil, err
+	}
+	p := NewWithDistribution(b, nil, dist)
+
+	racks := strings.Split(fields[1], "/")
+	if len(racks) != 2 {
+		return nil, fmt.Errorf("racks %q should be two racks separated by '/'", fields[1])
+	}
+	for i, rack := range racks {
+		tiles, err := parseRack(rack, dist)
+		if err != nil {
+			return nil, fmt.Errorf("rack %q: %w", rack, err)
+		}
+		if len(tiles) > RackSize {
+			return nil, fmt.Errorf("rack %q has more than %d tiles", rack, RackSize)
+		}
+		p.Racks[i] = tiles
+	}
+
+	scores := strings.Split(fields[2], "/")
+	if len(scores) != 2 {
+		return nil, fmt.Errorf("scores %q should be two scores separated by '/'", fields[2])
+	}
+	for i, score := range scores {
+		if p.Scores[i], err = strconv.Atoi(score); err != nil {
+			return nil, fmt.Errorf("bad score %q", score)
+		}
+	}
+
+	if p.ZeroTurns, err = strconv.Atoi(fields[3]); err != nil || p.ZeroTurns < 0 {
+		return nil, fmt.Errorf("bad zero-turn count %q", fields[3])
+	}
+
+	for _, op := range strings.Split(strings.Join(fields[4:], " "), ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(op), " ")
+		if name == "" {
+			continue
+		}
+		if name == "lex" {
+			p.Lexicon = strings.TrimSpace(value)
+		} else {
+			p.Options = append(p.Options, Option{Name: name, Value: strings.TrimSpace(value)})
+		}
+	}
+
+	if _, err := p.Unseen(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// parseBoard reads the rows of the board
+func parseBoard(s string, layout *board.Layout, dist *game.LetterDistribution) (*board.Board, error) {
+	b := board.NewWithLayout(layout)
+	rows := strings.Split(s, "/")
+	if len(rows) != b.Size() {
+		return nil, fmt.Errorf("board has %d rows, want %d", len(rows), b.Size())
+	}
+
+	for row, text := range rows {
+		col := 0
+		for text != "" {
+			// A run of digits counts empty squares
+			digits := strings.IndexFunc(text, func(r rune) bool { return !unicode.IsDigit(r) })
+			if digits < 0 {
+				digits = len(text)
+			}
+			if digits > 0 {
+				empty, _ := strconv.Atoi(text[:digits])
+				col += empty
+				text = text[digits:]
+				continue
+			}
+
+			symbol, rest, err := nextSymbol(text)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", row+1, err)
+			}
+			tile, err := tileFor(symbol, dist, false)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", row+1, err)
+			}
+			if col < b.Size() {
+				b.SetTile(row, col, &tile)
+			}
+			col++
+			text = rest
+		}
+
+		if col != b.Size() {
+			return nil, fmt.Errorf("row %d has %d squares, want %d", row+1, col, b.Size())
+		}
+	}
+	return b, nil
+}
+
+// parseRack reads a rack, '?' being a blank
+func parseRack(s string, dist *game.LetterDistribution) ([]game.Tile, error) {
+	var tiles []game.Tile
+	for s != "" {
+		symbol, rest, err := nextSymbol(s)
+		if err != nil {
+			return nil, err
+		}
+		tile, err := tileFor(symbol, dist, true)
+		if err != nil {
+			return nil, err
+		}
+		tiles = append(tiles, tile)
+		s = rest
+	}
+	return tiles, nil
+}
+
+// nextSymbol splits off the first tile symbol: one character, or several in brackets
+func nextSymbol(s string) (string, string, error) {
+	if s[0] != '[' {
+		_, size := utf8.DecodeRuneInString(s)
+		return s[:size], s[size:], nil
+	}
+	end := strings.IndexByte(s, ']')
+	if end < 2 {
+		return "", "", fmt.Errorf("unclosed or empty bracket in %q", s)
+	}
+	return s[1:end], s[end+1:], nil
+}
+
+// tileFor returns the tile for a symbol. On the board a lower-case symbol
+// is a blank; on a rack '?' is.
+func tileFor(symbol string, dist *game.LetterDistribution, rack bool) (game.Tile, error) {
+	if symbol == string(game.BlankLetter) && rack {
+		return dist.Tile(game.BlankLetter), nil
+	}
+
+	code, ok := dist.Alphabet().Code(symbol)
+	if !ok {
+		return game.Tile{}, fmt.Errorf("unknown tile %q", symbol)
+	}
+	first, _ := utf8.DecodeRuneInString(symbol)
+	if !rack && unicode.IsLower(first) {
+		return game.Tile{Letter: code, IsBlank: true}, nil
+	}
+	if rack && unicode.IsLower(first) {
+		return game.Tile{}, fmt.Errorf("lower-case tile %q on a rack; blanks are '?'", symbol)
+	}
+	return dist.Tile(code), nil
+}
+
+// Unseen counts the tiles the player to move cannot see: those in the bag
+// and on the opponent's rack, with '?' for blanks
+func (p *Position) Unseen() (map[rune]int, error) {
+	unseen := make(map[rune]int, len(p.dist.Letters)+1)
+	for _, letter := range p.dist.Letters {
+		unseen[letter] = p.dist.Count(letter)
+	}
+	unseen[game.BlankLetter] = p.dist.Blanks
+
+	take := func(tile game.Tile) error {
+		letter := tile.Letter
+		if tile.IsBlank {
+			letter = game.BlankLetter
+		}
+		if unseen[letter] == 0 {
+			return fmt.Errorf("position has more %s tiles than the distribution", p.display(letter))
+		}
+		unseen[letter]--
+		return nil
+	}
+
+	for row := 0; row < p.Board.Size(); row++ {
+		for col := 0; col < p.Board.Size(); col++ {
+			if tile := p.Board.GetTile(row, col); tile != nil {
+				if err := take(*tile); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, tile := range p.Racks[0] {
+		if err := take(tile); err != nil {
+			return nil, err
+		}
+	}
+
+	// The opponent's rack, when known, is among the unseen tiles, so it is
+	// checked against them without being taken out
+	left := maps.Clone(unseen)
+	for _, tile := range p.Racks[1] {
+		letter := tile.Letter
+		if tile.IsBlank {
+			letter = game.BlankLetter
+		}
+		if left[letter] == 0 {
+			return nil, fmt.Errorf("position has more %s tiles than the distribution", p.display(letter))
+		}
+		left[letter]--
+	}
+	return unseen, nil
+}
+
+// BagSize returns how many tiles are in the bag, taking the opponent to hold
+// a full rack if enough tiles remain
+func (p *Position) BagSize() int {
+	unseen, err := p.Unseen()
+	if err != nil {
+		return 0
+	}
+	total := 0
+	for _, count := range unseen {
+		total += count
+	}
+	return max(total-RackSize, 0)
+}
+
+// String writes the position as a CGP string
+func (p *Position) String() string {
+	var sb strings.Builder
+
+	for row := 0; row < p.Board.Size(); row++ {
+		if row > 0 {
+			sb.WriteByte('/')
+		}
+		empty := 0
+		for col := 0; col < p.Board.Size(); col++ {
+			tile := p.Board.GetTile(row, col)
+			if tile == nil {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			symbol := p.symbol(tile.Letter)
+			if tile.IsBlank {
+				symbol = strings.ToLower(symbol)
+			}
+			sb.WriteString(symbol)
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+	}
+
+	sb.WriteByte(' ')
+	for i, rack := range p.Racks {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+		for _, tile := range rack {
+			if tile.IsBlank {
+				sb.WriteRune(game.BlankLetter)
+			} else {
+				sb.WriteString(p.symbol(tile.Letter))
+			}
+		}
+	}
+
+	fmt.Fprintf(&sb, " %d/%d %d", p.Scores[0], p.Scores[1], p.ZeroTurns)
+	if p.Lexicon != "" {
+		fmt.Fprintf(&sb, " lex %s;", p.Lexicon)
+	}
+	for _, op := range p.Options {
+		sb.WriteString(" " + strings.TrimSpace(op.Name+" "+op.Value) + ";")
+	}
+	return sb.String()
+}
+
+// symbol returns how a tile is written, with brackets around several characters
+func (p *Position) symbol(code rune) string {
+	symbol := p.display(code)
+	if utf8.RuneCountInString(symbol) > 1 {
+		return "[" + symbol + "]"
+	}
+	return symbol
+}
+
+func (p *Position) display(code rune) string {
+	if code == game.BlankLetter {
+		return string(game.BlankLetter)
+	}
+	return p.dist.Alphabet().Display(code)
+}
@@ -0,0 +1,139 @@
+package cgp
+
+import (
+	"strings"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/game"
+)
+
+const catPosition = "15/15/15/15/15/15/15/7CAt5/15/15/15/15/15/15/15 AEINRS?/ 5/0 1 lex NWL2023; ld english;"
+
+func TestParse(t *testing.T) {
+	p, err := Parse(catPosition)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tile := p.Board.GetTile(7, 7); tile == nil || tile.Letter != 'C' || tile.Value != 3 {
+		t.Errorf("8H = %+v, want C worth 3", tile)
+	}
+	if tile := p.Board.GetTile(7, 9); tile == nil || tile.Letter != 'T' || !tile.IsBlank || tile.Value != 0 {
+		t.Errorf("8J = %+v, want a blank T", tile)
+	}
+	if got := len(p.Racks[0]); got != 7 || !p.Racks[0][6].IsBlank || len(p.Racks[1]) != 0 {
+		t.Errorf("racks = %+v, want AEINRS? and an unknown rack", p.Racks)
+	}
+	if p.Scores != [2]int{5, 0} || p.ZeroTurns != 1 || p.Lexicon != "NWL2023" {
+		t.Errorf("scores %v, zero turns %d, lexicon %q", p.Scores, p.ZeroTurns, p.Lexicon)
+	}
+	if len(p.Options) != 1 || p.Options[0] != (Option{Name: "ld", Value: "english"}) {
+		t.Errorf("options = %+v", p.Options)
+	}
+
+	if got := p.String(); got != catPosition {
+		t.Errorf("String() = %q, want %q", got, catPosition)
+	}
+}
+
+func TestUnseen(t *testing.T) {
+	p, err := Parse(catPosition)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unseen, err := p.Unseen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both blanks are seen, one on the board and one on the rack
+	for letter, want := range map[rune]int{'C': 1, 'A': 7, 'T': 6, 'S': 3, 'E': 11, '?': 0} {
+		if unseen[letter] != want {
+			t.Errorf("unseen %c = %d, want %d", letter, unseen[letter], want)
+		}
+	}
+	if got := p.BagSize(); got != 100-10-7 {
+		t.Errorf("BagSize() = %d, want %d", got, 100-10-7)
+	}
+
+	// A known opponent rack is still unseen by the player to move
+	p, err = Parse(strings.Repeat("15/", 14) + "15 A/Z 0/0 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unseen, err := p.Unseen(); err != nil || unseen['Z'] != 1 {
+		t.Errorf("unseen Z = %d, error %v, want 1 and no error", unseen['Z'], err)
+	}
+}
+
+func TestRoundTripFromBoard(t *testing.T) {
+	b := board.New()
+	b.SetTile(0, 0, &game.Tile{Letter: 'Q', Value: 10})
+	b.SetTile(14, 14, &game.Tile{Letter: 'I', Value: 1})
+	dist := game.EnglishDistribution()
+	p := New(b, []game.Tile{dist.Tile('Z'), dist.Tile(game.BlankLetter)})
+	p.Racks[1] = []game.Tile{dist.Tile('U')}
+	p.Scores = [2]int{-4, 321}
+
+	want := "Q14/15/15/15/15/15/15/15/15/15/15/15/15/15/14I Z?/U -4/321 0"
+	if got := p.String(); got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+	again, err := Parse(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != want || again.Racks[1][0].Letter != 'U' {
+		t.Errorf("parsed back as %q", again.String())
+	}
+}
+
+func TestParseDigraphs(t *testing.T) {
+	dist, err := game.ParseDistribution("spanish", strings.NewReader("A 12 1\nC 4 3\nCH 1 5\nO 9 1\n? 2 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := "15/15/15/15/15/15/15/7[CH]O[ch]5/15/15/15/15/15/15/15 [CH]A?/ 0/0 0"
+	_, err = ParseWithSetup(s, board.StandardLayout(), dist)
+	if err == nil || !strings.Contains(err.Error(), "more CH") {
+		t.Fatalf("three CH tiles parsed with error %v, want one about too many CH", err)
+	}
+
+	s = "15/15/15/15/15/15/15/7[CH]O[ch]5/15/15/15/15/15/15/15 CA?/ 0/0 0"
+	p, err := ParseWithSetup(s, board.StandardLayout(), dist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tile := p.Board.GetTile(7, 9); tile == nil || !tile.IsBlank || dist.Alphabet().Display(tile.Letter) != "CH" {
+		t.Errorf("8J = %+v, want a blank CH", tile)
+	}
+	if got := p.String(); got != s {
+		t.Errorf("String() = %q, want %q", got, s)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	empty := strings.Repeat("15/", 14) + "15"
+	for _, tc := range []struct {
+		name, cgp, want string
+	}{
+		{"too few fields", empty + " A/ 0/0", "needs a board"},
+		{"too few rows", "15/15 A/ 0/0 0", "rows"},
+		{"long row", strings.Repeat("15/", 14) + "14AB A/ 0/0 0", "squares"},
+		{"short row", strings.Repeat("15/", 14) + "13A A/ 0/0 0", "squares"},
+		{"unknown tile", strings.Repeat("15/", 14) + "14# A/ 0/0 0", "unknown tile"},
+		{"one rack", empty + " A 0/0 0", "two racks"},
+		{"long rack", empty + " ABCDEFGH/ 0/0 0", "more than 7"},
+		{"blank on the board", strings.Repeat("15/", 14) + "14? A/ 0/0 0", "unknown tile"},
+		{"bad score", empty + " A/ x/0 0", "bad score"},
+		{"bad zero turns", empty + " A/ 0/0 -1", "zero-turn"},
+		{"too many tiles", strings.Repeat("15/", 14) + "ZZ13 Z/ 0/0 0", "more Z"},
+		{"too many tiles with the opponent", strings.Repeat("15/", 14) + "Z14 A/Z 0/0 0", "more Z"},
+	} {
+		_, err := Parse(tc.cgp)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got error %v, want one mentioning %q", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package endgame
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+)
+
+// Solution is the result of solving an endgame
+type Solution struct {
+	Moves  []game.Move // Best play for both sides, starting with ours
+	Spread int         // Points we gain on the opponent from here to the end
+	Nodes  int         // Positions searched
+}
+
+// Solver searches endgames, where the bag is empty and both racks are known.
+// Going out earns twice the value of the opponent's rack; when both players
+// pass in a row the game ends and each loses the value of their own rack.
+//
+// The engine instead ends a game after six scoreless turns in a row. With
+// the bag empty that comes to the same: two passes bring back the position
+// they started from, with the same player to move, so playing on to six
+// cannot change the result. The rules part only for positions that follow
+// earlier scoreless turns, or lines with a placement worth no points, and
+// the solver counts neither.
+type Solver struct {
+	lexicon  *gaddag.Compact
+	dist     *game.LetterDistribution
+	maxDepth int
+}
+
+// New creates a solver that searches every line to the end of the game
+func New(lexicon gaddag.Lexicon, dist *game.LetterDistribution) *Solver {
+	return &Solver{lexicon: lexicon.Compact(), dist: dist}
+}
+
+// SetMaxDepth limits the search to that many turns, after which a position
+// is scored as if both players were stuck. Zero searches to the end.
+func (s *Solver) SetMaxDepth(depth int) {
+	s.maxDepth = depth
+}
+
+// Solve finds the best play for the player holding ours, who is to move,
+// against an opponent holding theirs. The board is changed during the
+// search but left as it was.
+func (s *Solver) Solve(b *board.Board, ours, theirs []game.Tile) Solution {
+	sr := &search{
+		solver: s,
+		board:  b,
+		gen:    generator.NewWithDistribution(s.lexicon, b, s.dist),
+		table:  make(map[uint64]entry),
+		keys:   newZobrist(b.Size()),
+	}
+	sr.racks[0], _ = game.NewRack(s.dist, ours)
+	sr.racks[1], _ = game.NewRack(s.dist, theirs)
+
+	value, line := sr.negamax(0, 0, false, -math.MaxInt32, math.MaxInt32)
+	return Solution{Moves: line, Spread: value, Nodes: sr.nodes}
+}
+
+// search is the state of one Solve call
+type search struct {
+	solver *Solver
+	board  *board.Board
+	gen    *generator.Generator // Kept in step with the board as moves are played and undone
+	racks  [2]game.Rack
+	table  map[uint64]entry
+	keys   *zobrist
+	hash   uint64 // Tiles placed during the search
+	nodes  int
+}
+
+type bound int
+
+const (
+	exact bound = iota
+	lower       // The value is at least this
+	upper       // The value is at most this
+)
+
+// entry is a searched position in the transposition table
+type entry struct {
+	depth int // Turns left to search when stored; -1 means to the end
+	value int
+	bound bound
+	line  []game.Move
+}
+
+// negamax returns the best spread for the player to move and the line that
+// gets it. ply counts turns from the root; passed says the last turn was a pass.
+func (sr *search) negamax(player, ply int, passed bool, alpha, beta int) (int, []game.Move) {
+	sr.nodes++
+	me, them := &sr.racks[player], &sr.racks[1-player]
+
+	if sr.solver.maxDepth > 0 && ply >= sr.solver.maxDepth {
+		return them.Value() - me.Value(), nil
+	}
+
+	depth := -1
+	if sr.solver.maxDepth > 0 {
+		depth = sr.solver.maxDepth - ply
+	}
+	key := sr.positionKey(player, passed)
+	if e, ok := sr.table[key]; ok && (e.depth == -1 || e.depth >= depth) {
+		switch {
+		case e.bound == exact,
+			e.bound == lower && e.value >= beta,
+			e.bound == upper && e.value <= alpha:
+			return e.value, e.line
+		}
+	}
+
+	origAlpha := alpha
+	best, bestLine := math.MinInt32, []game.Move(nil)
+
+	for _, move := range sr.moves(player, key) {
+		var value int
+		var line []game.Move
+
+		if move.Kind == game.MovePass {
+			if passed {
+				// Two passes in a row end the game
+				value = them.Value() - me.Value()
+			} else {
+				value, line = sr.negamax(1-player, ply+1, true, -beta, -alpha)
+				value = -value
+			}
+		} else {
+			sr.play(player, move)
+			if me.Len() == 0 {
+				// Going out ends the game
+				value = move.Score + 2*them.Value()
+			} else {
+				value, line = sr.negamax(1-player, ply+1, false, -beta, -alpha)
+				value = move.Score - value
+			}
+			sr.undo(player, move)
+		}
+
+		if value > best {
+			best, bestLine = value, append([]game.Move{move}, line...)
+		}
+		alpha = max(alpha, value)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	e := entry{depth: depth, value: best, bound: exact, line: bestLine}
+	if best <= origAlpha {
+		e.bound = upper
+	} else if best >= beta {
+		e.bound = lower
+	}
+	sr.table[key] = e
+
+	return best, bestLine
+}
+
+// moves lists the player's placements and a pass, trying the previous best
+// move here first, then moves that go out, then the highest scores
+func (sr *search) moves(player int, key uint64) []game.Move {
+	rack := &sr.racks[player]
+	moves := sr.gen.GenerateMoves(rack.Tiles())
+
+	var hint *game.Move
+	if e, ok := sr.table[key]; ok && len(e.line) > 0 {
+		hint = &e.line[0]
+	}
+
+	rank := func(m game.Move) int {
+		switch {
+		case hint != nil && sameMove(m, *hint):
+			return 0
+		case len(m.TilesPlaced) == rack.Len():
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return rank(moves[i]) < rank(moves[j])
+	})
+
+	return append(moves, game.Move{Kind: game.MovePass})
+}
+
+// play puts a placement on the board and takes its tiles off the rack
+func (sr *search) play(player int, move game.Move) {
+	for _, placed := range move.TilesPlaced {
+		tile := placed.Tile
+		sr.board.SetTile(placed.Position.Row, placed.Position.Col, &tile)
+		sr.racks[player].Remove(tile)
+		sr.hash ^= sr.keys.tile(placed, sr.board.Size(), sr.solver.dist)
+	}
+	sr.gen.UpdateCrossChecks(move)
+}
+
+// undo takes a placement back
+func (sr *search) undo(player int, move game.Move) {
+	for _, placed := range move.TilesPlaced {
+		sr.board.SetTile(placed.Position.Row, placed.Position.Col, nil)
+		sr.racks[player].Add(placed.Tile)
+		sr.hash ^= sr.keys.tile(placed, sr.board.Size(), sr.solver.dist)
+	}
+	sr.gen.UpdateCrossChecks(move)
+}
+
+// positionKey identifies a position: the tiles placed, both racks, who is
+// to move and whether the last turn was a pass
+func (sr *search) positionKey(player int, passed bool) uint64 {
+	key := sr.hash
+	for p := range sr.racks {
+		rack := &sr.racks[p]
+		for i := range sr.solver.dist.Letters {
+			key ^= sr.keys.rack[p][i][min(rack.CountAt(i), maxCount-1)]
+		}
+		key ^= sr.keys.blanks[p][min(rack.Blanks(), maxCount-1)]
+	}
+	if player == 1 {
+		key ^= sr.keys.player
+	}
+	if passed {
+		key ^= sr.keys.passed
+	}
+	return key
+}
+
+func sameMove(a, b game.Move) bool {
+	if a.Kind != b.Kind || a.Word != b.Word || a.Position != b.Position || a.Direction != b.Direction {
+		return false
+	}
+	for i := range a.TilesPlaced {
+		if i >= len(b.TilesPlaced) || a.TilesPlaced[i] != b.TilesPlaced[i] {
+			return false
+		}
+	}
+	return len(a.TilesPlaced) == len(b.TilesPlaced)
+}
+
+// maxCount bounds the per-letter counts given their own keys
+const maxCount = 8
+
+// zobrist holds the random keys that are combined into position keys
+type zobrist struct {
+	squares [][2 * game.MaxLetters]uint64 // [square][letter, or letter+MaxLetters for a blank]
+	rack    [2][game.MaxLetters][maxCount]uint64
+	blanks  [2][maxCount]uint64
+	player  uint64
+	passed  uint64
+}
+
+func newZobrist(size int) *zobrist {
+	rng := rand.New(rand.NewSource(1))
+	z := &zobrist{squares: make([][2 * game.MaxLetters]uint64, size*size)}
+	for i := range z.squares {
+		for j := range z.squares[i] {
+			z.squares[i][j] = rng.Uint64()
+		}
+	}
+	for p := range z.rack {
+		for i := range z.rack[p] {
+			for n := range z.rack[p][i] {
+				z.rack[p][i][n] = rng.Uint64()
+			}
+		}
+		for n := range z.blanks[p] {
+			z.blanks[p][n] = rng.Uint64()
+		}
+	}
+	z.player = rng.Uint64()
+	z.passed = rng.Uint64()
+	return z
+}
+
+// tile returns the key for a placed tile
+func (z *zobrist) tile(placed game.PlacedTile, size int, dist *game.LetterDistribution) uint64 {
+	i, _ := dist.Index(placed.Tile.Letter)
+	if placed.Tile.IsBlank {
+		i += game.MaxLetters
+	}
+	return z.squares[placed.Position.Row*size+placed.Position.Col][i]
+}
@@ -0,0 +1,220 @@
+package endgame
+
+import (
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/engine"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+)
+
+var testWords = []string{
+	"CAT", "CATS", "SCAT", "ACT", "ACTS", "AT", "AS", "TA", "TAS", "SAT",
+	"TEA", "TEAS", "EAT", "EATS", "SEAT", "ATE", "ETA", "ETAS", "EAST",
+	"ES", "TE", "ET", "RE", "ER", "AE", "EA",
+}
+
+func newTestLexicon() *gaddag.GADDAG {
+	g := gaddag.New()
+	for _, word := range testWords {
+		g.Add(word)
+	}
+	return g
+}
+
+func testBoard() *board.Board {
+	b := board.New()
+	for i, letter := range "CAT" {
+		b.SetTile(7, 7+i, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+	}
+	return b
+}
+
+func tiles(letters string) []game.Tile {
+	dist := game.EnglishDistribution()
+	var rack []game.Tile
+	for _, letter := range letters {
+		rack = append(rack, dist.Tile(letter))
+	}
+	return rack
+}
+
+func TestSolveGoingOut(t *testing.T) {
+	solver := New(newTestLexicon(), game.EnglishDistribution())
+	sol := solver.Solve(testBoard(), tiles("S"), tiles("QZ"))
+
+	// CATS or SCAT scores 6, plus twice the 20 points stuck on their rack
+	if sol.Spread != 46 {
+		t.Errorf("spread = %d, want 46", sol.Spread)
+	}
+	if len(sol.Moves) != 1 || sol.Moves[0].Kind != game.MovePlace || len(sol.Moves[0].TilesPlaced) != 1 {
+		t.Errorf("want a single move going out, got %+v", sol.Moves)
+	}
+}
+
+func TestSolveBothStuck(t *testing.T) {
+	solver := New(newTestLexicon(), game.EnglishDistribution())
+	sol := solver.Solve(testBoard(), tiles("Q"), tiles("ZX"))
+
+	// Both pass and each loses their own rack: -10 for us, +18 from them
+	if sol.Spread != 8 {
+		t.Errorf("spread = %d, want 8", sol.Spread)
+	}
+	if len(sol.Moves) != 2 || sol.Moves[0].Kind != game.MovePass || sol.Moves[1].Kind != game.MovePass {
+		t.Errorf("want two passes, got %+v", sol.Moves)
+	}
+}
+
+func TestSolveMatchesMinimax(t *testing.T) {
+	lexicon := newTestLexicon()
+	dist := game.EnglishDistribution()
+	solver := New(lexicon, dist)
+
+	for _, racks := range [][2]string{
+		{"SE", "AT"},
+		{"EAS", "TQ"},
+		{"TE", "SAE"},
+		{"QE", "S"},
+	} {
+		b := testBoard()
+		sol := solver.Solve(b, tiles(racks[0]), tiles(racks[1]))
+
+		ours, _ := game.NewRack(dist, tiles(racks[0]))
+		theirs, _ := game.NewRack(dist, tiles(racks[1]))
+		want := minimax(lexicon, dist, b, [2]game.Rack{ours, theirs}, 0, false)
+		if sol.Spread != want {
+			t.Errorf("%s vs %s: spread = %d, want %d", racks[0], racks[1], sol.Spread, want)
+		}
+
+		// Replaying the line must give the same spread
+		if got := replay(t, dist, testBoard(), sol.Moves, [2]game.Rack{ours, theirs}); got != sol.Spread {
+			t.Errorf("%s vs %s: line scores %d, solution says %d", racks[0], racks[1], got, sol.Spread)
+		}
+
+		if b.GetTile(7, 10) != nil || b.GetTile(6, 7) != nil || b.GetTile(8, 8) != nil {
+			t.Errorf("%s vs %s: solving changed the board", racks[0], racks[1])
+		}
+	}
+}
+
+func TestSolveMaxDepth(t *testing.T) {
+	solver := New(newTestLexicon(), game.EnglishDistribution())
+	solver.SetMaxDepth(1)
+	sol := solver.Solve(testBoard(), tiles("EAS"), tiles("TQ"))
+	if len(sol.Moves) != 1 {
+		t.Errorf("depth 1 gave a line of %d moves", len(sol.Moves))
+	}
+}
+
+func TestTwoPassesMatchEngineRule(t *testing.T) {
+	lexicon := newTestLexicon()
+	dist := game.EnglishDistribution()
+	solver := New(lexicon, dist)
+
+	for _, racks := range [][2]string{
+		{"Q", "ZX"},
+		{"QE", "S"},
+		{"SE", "AT"},
+	} {
+		sol := solver.Solve(testBoard(), tiles(racks[0]), tiles(racks[1]))
+
+		ours, _ := game.NewRack(dist, tiles(racks[0]))
+		theirs, _ := game.NewRack(dist, tiles(racks[1]))
+		want := minimaxScoreless(lexicon, dist, testBoard(), [2]game.Rack{ours, theirs}, 0, 0)
+		if sol.Spread != want {
+			t.Errorf("%s vs %s: spread = %d, but %d ending after %d scoreless turns", racks[0], racks[1], sol.Spread, want, engine.MaxScorelessTurns)
+		}
+	}
+}
+
+// minimax searches every line without pruning or a transposition table
+func minimax(lexicon gaddag.Lexicon, dist *game.LetterDistribution, b *board.Board, racks [2]game.Rack, player int, passed bool) int {
+	me, them := racks[player], racks[1-player]
+
+	// Passing
+	best := them.Value() - me.Value()
+	if !passed {
+		best = -minimax(lexicon, dist, b, racks, 1-player, true)
+	}
+
+	for _, move := range generator.NewWithDistribution(lexicon, b, dist).GenerateMoves(me.Tiles()) {
+		next := racks
+		for _, placed := range move.TilesPlaced {
+			tile := placed.Tile
+			b.SetTile(placed.Position.Row, placed.Position.Col, &tile)
+			next[player].Remove(tile)
+		}
+
+		value := move.Score + 2*them.Value()
+		if next[player].Len() > 0 {
+			value = move.Score - minimax(lexicon, dist, b, next, 1-player, false)
+		}
+		best = max(best, value)
+
+		for _, placed := range move.TilesPlaced {
+			b.SetTile(placed.Position.Row, placed.Position.Col, nil)
+		}
+	}
+	return best
+}
+
+// minimaxScoreless is minimax with the engine's rule for ending the game:
+// after MaxScorelessTurns turns in a row without points
+func minimaxScoreless(lexicon gaddag.Lexicon, dist *game.LetterDistribution, b *board.Board, racks [2]game.Rack, player, scoreless int) int {
+	me, them := racks[player], racks[1-player]
+
+	// Passing
+	best := them.Value() - me.Value()
+	if scoreless+1 < engine.MaxScorelessTurns {
+		best = -minimaxScoreless(lexicon, dist, b, racks, 1-player, scoreless+1)
+	}
+
+	for _, move := range generator.NewWithDistribution(lexicon, b, dist).GenerateMoves(me.Tiles()) {
+		next := racks
+		b.Place(move)
+		next[player].RemoveTiles(move.TilesUsed())
+
+		value := move.Score + 2*them.Value()
+		if next[player].Len() > 0 {
+			after := 0
+			if move.Score == 0 {
+				after = scoreless + 1
+			}
+			value = move.Score - minimaxScoreless(lexicon, dist, b, next, 1-player, after)
+		}
+		best = max(best, value)
+		b.Unplace(move)
+	}
+	return best
+}
+
+// replay plays a line and returns the spread it earns for the first player
+func replay(t *testing.T, dist *game.LetterDistribution, b *board.Board, line []game.Move, racks [2]game.Rack) int {
+	t.Helper()
+
+	spread, sign := 0, 1
+	for i, move := range line {
+		player := i % 2
+		if move.Kind == game.MovePass {
+			if i > 0 && line[i-1].Kind == game.MovePass {
+				return spread + sign*(racks[1-player].Value()-racks[player].Value())
+			}
+		} else {
+			for _, placed := range move.TilesPlaced {
+				tile := placed.Tile
+				b.SetTile(placed.Position.Row, placed.Position.Col, &tile)
+				if err := racks[player].Remove(tile); err != nil {
+					t.Fatalf("move %d plays a tile not on the rack: %v", i, err)
+				}
+			}
+			spread += sign * move.Score
+			if racks[player].Len() == 0 {
+				return spread + sign*2*racks[1-player].Value()
+			}
+		}
+		sign = -sign
+	}
+	t.Fatalf("line of %d moves does not reach the end of the game", len(line))
+	return 0
+}
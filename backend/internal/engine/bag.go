@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"math/rand"
+	"tiletactics/backend/internal/game"
+)
+
+// Bag holds the tiles that have not yet been drawn
+type Bag struct {
+	tiles []game.Tile
+	rng   *rand.Rand
+}
+
+// NewBag creates a shuffled bag containing every tile in the distribution
+func NewBag(dist *game.LetterDistribution, rng *rand.Rand) *Bag {
+	bag := &Bag{rng: rng}
+
+	// Fill in the distribution's letter order, not map order, so a given
+	// seed is reproducible
+	for _, letter := range append(append([]rune(nil), dist.Letters...), game.BlankLetter) {
+		for i := 0; i < dist.Count(letter); i++ {
+			bag.tiles = append(bag.tiles, dist.Tile(letter))
+		}
+	}
+	bag.shuffle()
+
+	return bag
+}
+
+// Len returns the number of tiles left in the bag
+func (b *Bag) Len() int {
+	return len(b.tiles)
+}
+
+// Draw removes up to n tiles from the bag
+func (b *Bag) Draw(n int) []game.Tile {
+	if n > len(b.tiles) {
+		n = len(b.tiles)
+	}
+
+	drawn := make([]game.Tile, n)
+	copy(drawn, b.tiles[len(b.tiles)-n:])
+	b.tiles = b.tiles[:len(b.tiles)-n]
+
+	return drawn
+}
+
+// Return puts tiles back into the bag and reshuffles it
+func (b *Bag) Return(tiles []game.Tile) {
+	for _, tile := range tiles {
+		if tile.IsBlank {
+			tile = game.Tile{Letter: game.BlankLetter, Value: 0, IsBlank: true}
+		}
+		b.tiles = append(b.tiles, tile)
+	}
+	b.shuffle()
+}
+
+// Counts returns how many of each letter are left in the bag, with blanks as '?'
+func (b *Bag) Counts() map[rune]int {
+	counts := make(map[rune]int)
+	for _, tile := range b.tiles {
+		counts[tile.Letter]++
+	}
+	return counts
+}
+
+func (b *Bag) shuffle() {
+	b.rng.Shuffle(len(b.tiles), func(i, j int) {
+		b.tiles[i], b.tiles[j] = b.tiles[j], b.tiles[i]
+	})
+}
@@ -0,0 +1,310 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/scorer"
+	"tiletactics/backend/internal/validator"
+)
+
+const (
+	// NumPlayers is the number of players in a game
+	NumPlayers = 2
+
+	// RackSize is the number of tiles a player holds when the bag allows it
+	RackSize = 7
+
+	// MinBagForExchange is the number of tiles that must be in the bag to exchange
+	MinBagForExchange = game.MinBagForExchange
+
+	// MaxScorelessTurns ends the game after this many consecutive scoreless turns
+	MaxScorelessTurns = 6
+)
+
+var (
+	ErrGameOver           = errors.New("game is over")
+	ErrNoTilesToExchange  = errors.New("no tiles selected to exchange")
+	ErrExchangeNotAllowed = errors.New("not enough tiles in the bag to exchange")
+)
+
+// Action is the kind of turn a player took
+type Action int
+
+const (
+	ActionPlay Action = iota
+	ActionExchange
+	ActionPass
+)
+
+// Turn records a single completed turn
+type Turn struct {
+	Player    int
+	Action    Action
+	Move      game.Move   // Set for ActionPlay
+	Exchanged []game.Tile // Set for ActionExchange
+	Score     int
+}
+
+// Game owns the full state of a two-player game
+type Game struct {
+	board          *board.Board
+	validator      *validator.Validator
+	dist           *game.LetterDistribution
+	bag            *Bag
+	racks          [NumPlayers]game.Rack
+	scores         [NumPlayers]int
+	current        int
+	scorelessTurns int
+	over           bool
+	history        []Turn
+}
+
+// New starts a standard game with a freshly shuffled bag and deals both racks
+func New(lexicon gaddag.Lexicon, seed int64) *Game {
+	return NewWithSetup(lexicon, board.StandardLayout(), game.EnglishDistribution(), seed)
+}
+
+// NewWithSetup starts a game on the given board layout with a bag filled
+// from the given letter distribution
+func NewWithSetup(lexicon gaddag.Lexicon, layout *board.Layout, dist *game.LetterDistribution, seed int64) *Game {
+	g := &Game{
+		board:     board.NewWithLayout(layout),
+		validator: validator.NewWithDistribution(lexicon, dist),
+		dist:      dist,
+		bag:       NewBag(dist, rand.New(rand.NewSource(seed))),
+	}
+
+	for player := range g.racks {
+		g.racks[player], _ = game.NewRack(dist, g.bag.Draw(RackSize))
+	}
+
+	return g
+}
+
+// Distribution returns the letter distribution the game is played with
+func (g *Game) Distribution() *game.LetterDistribution {
+	return g.dist
+}
+
+// Board returns the game board
+func (g *Game) Board() *board.Board {
+	return g.board
+}
+
+// CurrentPlayer returns the index of the player to move
+func (g *Game) CurrentPlayer() int {
+	return g.current
+}
+
+// Rack returns a copy of a player's rack, in distribution order with blanks last
+func (g *Game) Rack(player int) []game.Tile {
+	return g.racks[player].Tiles()
+}
+
+// Score returns a player's current score
+func (g *Game) Score(player int) int {
+	return g.scores[player]
+}
+
+// BagLen returns the number of tiles left in the bag
+func (g *Game) BagLen() int {
+	return g.bag.Len()
+}
+
+// ScorelessTurns returns the number of consecutive scoreless turns
+func (g *Game) ScorelessTurns() int {
+	return g.scorelessTurns
+}
+
+// History returns every turn played so far
+func (g *Game) History() []Turn {
+	return g.history
+}
+
+// Unseen returns the tiles the given player cannot see: the bag plus the
+// opponent's rack, in the form expected by evaluator.New
+func (g *Game) Unseen(player int) map[rune]int {
+	unseen := g.bag.Counts()
+	for _, tile := range g.racks[opponent(player)].Tiles() {
+		unseen[rackLetter(tile)]++
+	}
+	return unseen
+}
+
+// IsOver returns true once the game has finished
+func (g *Game) IsOver() bool {
+	return g.over
+}
+
+// Play places a move for the current player, scores it and refills their rack.
+// Illegal moves are rejected with the error from validator.Validate.
+func (g *Game) Play(move game.Move) error {
+	if g.over {
+		return ErrGameOver
+	}
+
+	if err := g.validator.Validate(g.board, g.racks[g.current].Tiles(), move); err != nil {
+		return err
+	}
+
+	rack, err := removeTiles(g.racks[g.current], move.TilesUsed())
+	if err != nil {
+		return err
+	}
+
+	// Blanks are always worth nothing, whatever the caller sent
+	move.TilesPlaced = append([]game.PlacedTile(nil), move.TilesPlaced...)
+	for i := range move.TilesPlaced {
+		tile := &move.TilesPlaced[i].Tile
+		if tile.IsBlank {
+			tile.Value = 0
+		} else {
+			tile.Value = g.dist.Value(tile.Letter)
+		}
+	}
+
+	// Score against the board before the tiles are placed
+	move.Score = scorer.NewWithDistribution(g.board, g.dist).ScoreMove(move)
+
+	g.board.Place(move)
+
+	g.racks[g.current] = refill(rack, g.bag.Draw(len(move.TilesPlaced)))
+	g.scores[g.current] += move.Score
+	g.history = append(g.history, Turn{
+		Player: g.current,
+		Action: ActionPlay,
+		Move:   move,
+		Score:  move.Score,
+	})
+
+	if g.racks[g.current].Len() == 0 && g.bag.Len() == 0 {
+		g.finishOut(g.current)
+		return nil
+	}
+
+	g.endTurn(move.Score)
+	return nil
+}
+
+// Apply takes the current player's turn with a move of any kind
+func (g *Game) Apply(move game.Move) error {
+	switch move.Kind {
+	case game.MoveExchange:
+		return g.Exchange(move.Exchanged)
+	case game.MovePass:
+		return g.Pass()
+	default:
+		return g.Play(move)
+	}
+}
+
+// Exchange swaps tiles from the current player's rack with tiles from the bag
+func (g *Game) Exchange(tiles []game.Tile) error {
+	if g.over {
+		return ErrGameOver
+	}
+	if len(tiles) == 0 {
+		return ErrNoTilesToExchange
+	}
+	if g.bag.Len() < MinBagForExchange {
+		return ErrExchangeNotAllowed
+	}
+
+	rack, err := removeTiles(g.racks[g.current], tiles)
+	if err != nil {
+		return err
+	}
+
+	// New tiles are drawn before the old ones go back in
+	drawn := g.bag.Draw(len(tiles))
+	g.bag.Return(tiles)
+	g.racks[g.current] = refill(rack, drawn)
+
+	g.history = append(g.history, Turn{
+		Player:    g.current,
+		Action:    ActionExchange,
+		Exchanged: tiles,
+	})
+
+	g.endTurn(0)
+	return nil
+}
+
+// Pass ends the current player's turn without playing
+func (g *Game) Pass() error {
+	if g.over {
+		return ErrGameOver
+	}
+
+	g.history = append(g.history, Turn{
+		Player: g.current,
+		Action: ActionPass,
+	})
+
+	g.endTurn(0)
+	return nil
+}
+
+// endTurn applies the scoreless-turn rule and hands the turn to the opponent
+func (g *Game) endTurn(score int) {
+	if score > 0 {
+		g.scorelessTurns = 0
+	} else {
+		g.scorelessTurns++
+	}
+
+	if g.scorelessTurns >= MaxScorelessTurns {
+		// Each player loses the value of the tiles left on their rack
+		for player := range g.racks {
+			g.scores[player] -= g.racks[player].Value()
+		}
+		g.over = true
+		return
+	}
+
+	g.current = opponent(g.current)
+}
+
+// finishOut ends the game after a player uses their last tile with the bag
+// empty; they gain twice the value of their opponent's remaining tiles
+func (g *Game) finishOut(player int) {
+	g.scores[player] += 2 * g.racks[opponent(player)].Value()
+	g.scorelessTurns = 0
+	g.over = true
+}
+
+func opponent(player int) int {
+	return (player + 1) % NumPlayers
+}
+
+// rackLetter returns the letter a tile occupies on a rack, with blanks as '?'
+func rackLetter(tile game.Tile) rune {
+	if tile.IsBlank {
+		return game.BlankLetter
+	}
+	return tile.Letter
+}
+
+// removeTiles returns the rack without the given tiles; a blank matches any
+// blank on the rack regardless of the letter it was designated as
+func removeTiles(rack game.Rack, tiles []game.Tile) (game.Rack, error) {
+	for _, tile := range tiles {
+		if err := rack.Remove(tile); err != nil {
+			return rack, fmt.Errorf("%w: %c", validator.ErrTileNotOnRack, rackLetter(tile))
+		}
+	}
+	return rack, nil
+}
+
+// refill returns the rack with the drawn tiles added
+func refill(rack game.Rack, drawn []game.Tile) game.Rack {
+	for _, tile := range drawn {
+		// Tiles come from a bag filled from the same distribution
+		rack.Add(tile)
+	}
+	return rack
+}
@@ -0,0 +1,256 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/validator"
+)
+
+func newTestGame(t *testing.T) *Game {
+	t.Helper()
+
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "CATS", "AT", "TA", "ZA"} {
+		g.Add(word)
+	}
+
+	return New(g, 1)
+}
+
+func rackOf(letters string) game.Rack {
+	dist := game.EnglishDistribution()
+	tiles := make([]game.Tile, 0, len(letters))
+	for _, letter := range letters {
+		tiles = append(tiles, dist.Tile(letter))
+	}
+	rack, _ := game.NewRack(dist, tiles)
+	return rack
+}
+
+func catMove() game.Move {
+	return game.Move{
+		Word:      "CAT",
+		Position:  game.Position{Row: 7, Col: 7},
+		Direction: game.Horizontal,
+		TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 7, Col: 7}, Tile: game.Tile{Letter: 'C', Value: 3}},
+			{Position: game.Position{Row: 7, Col: 8}, Tile: game.Tile{Letter: 'A', Value: 1}},
+			{Position: game.Position{Row: 7, Col: 9}, Tile: game.Tile{Letter: 'T', Value: 1}},
+		},
+	}
+}
+
+func TestNewGameDealsRacks(t *testing.T) {
+	g := newTestGame(t)
+
+	total := 0
+	for _, count := range game.TileDistribution {
+		total += count
+	}
+
+	for player := 0; player < NumPlayers; player++ {
+		if got := len(g.Rack(player)); got != RackSize {
+			t.Errorf("player %d rack has %d tiles, want %d", player, got, RackSize)
+		}
+	}
+	if got, want := g.BagLen(), total-NumPlayers*RackSize; got != want {
+		t.Errorf("BagLen() = %d, want %d", got, want)
+	}
+}
+
+func TestSameSeedSameGame(t *testing.T) {
+	a := newTestGame(t)
+	b := newTestGame(t)
+
+	for player := 0; player < NumPlayers; player++ {
+		ra, rb := a.Rack(player), b.Rack(player)
+		for i := range ra {
+			if ra[i] != rb[i] {
+				t.Fatalf("player %d racks differ with the same seed: %v vs %v", player, ra, rb)
+			}
+		}
+	}
+}
+
+func TestPlay(t *testing.T) {
+	g := newTestGame(t)
+	g.racks[0] = rackOf("CATXYZQ")
+	bagBefore := g.BagLen()
+
+	if err := g.Play(catMove()); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+
+	// C(3) + A(1) + T(1) doubled by the centre star
+	if got := g.Score(0); got != 10 {
+		t.Errorf("Score(0) = %d, want 10", got)
+	}
+	if got := g.Board().GetTile(7, 8); got == nil || got.Letter != 'A' {
+		t.Errorf("expected A at (7,8), got %v", got)
+	}
+	if got := len(g.Rack(0)); got != RackSize {
+		t.Errorf("rack has %d tiles after drawing, want %d", got, RackSize)
+	}
+	if got := g.BagLen(); got != bagBefore-3 {
+		t.Errorf("BagLen() = %d, want %d", got, bagBefore-3)
+	}
+	if got := g.CurrentPlayer(); got != 1 {
+		t.Errorf("CurrentPlayer() = %d, want 1", got)
+	}
+}
+
+func TestPlayRejectsTilesNotOnRack(t *testing.T) {
+	g := newTestGame(t)
+	g.racks[0] = rackOf("CAXYZQE")
+
+	err := g.Play(catMove())
+	if !errors.Is(err, validator.ErrTileNotOnRack) {
+		t.Fatalf("Play() error = %v, want ErrTileNotOnRack", err)
+	}
+	if !g.Board().IsCompletelyEmpty() {
+		t.Error("rejected move should not touch the board")
+	}
+}
+
+func TestPlayWithBlank(t *testing.T) {
+	g := newTestGame(t)
+	g.racks[0] = rackOf("?ATXYZQ")
+
+	move := catMove()
+	move.TilesPlaced[0].Tile = game.Tile{Letter: 'C', Value: 3, IsBlank: true}
+
+	if err := g.Play(move); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+	if got := g.Score(0); got != 4 {
+		t.Errorf("Score(0) = %d, want 4 (blank scores nothing)", got)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	g := newTestGame(t)
+	rack := g.Rack(0)
+	bagBefore := g.BagLen()
+
+	if err := g.Exchange(rack[:3]); err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if got := len(g.Rack(0)); got != RackSize {
+		t.Errorf("rack has %d tiles after exchange, want %d", got, RackSize)
+	}
+	if got := g.BagLen(); got != bagBefore {
+		t.Errorf("BagLen() = %d, want %d", got, bagBefore)
+	}
+	if got := g.ScorelessTurns(); got != 1 {
+		t.Errorf("ScorelessTurns() = %d, want 1", got)
+	}
+}
+
+func TestExchangeNeedsFullBag(t *testing.T) {
+	g := newTestGame(t)
+	g.bag.Draw(g.BagLen() - (MinBagForExchange - 1))
+
+	if err := g.Exchange(g.Rack(0)[:1]); !errors.Is(err, ErrExchangeNotAllowed) {
+		t.Errorf("Exchange() error = %v, want ErrExchangeNotAllowed", err)
+	}
+}
+
+func TestApplyDispatchesOnKind(t *testing.T) {
+	g := newTestGame(t)
+	g.racks[0] = rackOf("CATXYZQ")
+	exchanged := rackOf("QXZ")
+
+	if err := g.Apply(game.Move{Kind: game.MoveExchange, Exchanged: exchanged.Tiles()}); err != nil {
+		t.Fatalf("Apply(exchange) error = %v", err)
+	}
+	if err := g.Apply(game.Move{Kind: game.MovePass}); err != nil {
+		t.Fatalf("Apply(pass) error = %v", err)
+	}
+
+	history := g.History()
+	if len(history) != 2 || history[0].Action != ActionExchange || history[1].Action != ActionPass {
+		t.Fatalf("history = %+v, want an exchange then a pass", history)
+	}
+	if got := len(history[0].Exchanged); got != 3 {
+		t.Errorf("exchanged %d tiles, want 3", got)
+	}
+}
+
+func TestScorelessTurnsEndGame(t *testing.T) {
+	g := newTestGame(t)
+	g.racks[0] = rackOf("AB")
+	g.racks[1] = rackOf("Q")
+
+	for i := 0; i < MaxScorelessTurns; i++ {
+		if g.IsOver() {
+			t.Fatalf("game ended after %d scoreless turns", i)
+		}
+		if err := g.Pass(); err != nil {
+			t.Fatalf("Pass() error = %v", err)
+		}
+	}
+
+	if !g.IsOver() {
+		t.Fatal("game should end after MaxScorelessTurns")
+	}
+	if got := g.Score(0); got != -4 {
+		t.Errorf("Score(0) = %d, want -4", got)
+	}
+	if got := g.Score(1); got != -10 {
+		t.Errorf("Score(1) = %d, want -10", got)
+	}
+	if err := g.Pass(); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Pass() after game over error = %v, want ErrGameOver", err)
+	}
+}
+
+func TestGoingOut(t *testing.T) {
+	g := newTestGame(t)
+	g.bag.Draw(g.BagLen())
+	g.racks[0] = rackOf("CAT")
+	g.racks[1] = rackOf("QZ")
+
+	if err := g.Play(catMove()); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+
+	if !g.IsOver() {
+		t.Fatal("game should end when a player goes out with the bag empty")
+	}
+	// 10 for CAT plus twice Q+Z
+	if got := g.Score(0); got != 50 {
+		t.Errorf("Score(0) = %d, want 50", got)
+	}
+}
+
+func TestNewWithSetupUsesDistribution(t *testing.T) {
+	dist, err := game.LoadDistribution("../../distributions/super.txt")
+	if err != nil {
+		t.Fatalf("LoadDistribution() error = %v", err)
+	}
+
+	g := NewWithSetup(gaddag.New(), board.SuperLayout(), dist, 1)
+
+	if got := g.Board().Size(); got != 21 {
+		t.Errorf("Board().Size() = %d, want 21", got)
+	}
+	if got, want := g.BagLen(), 200-NumPlayers*RackSize; got != want {
+		t.Errorf("BagLen() = %d, want %d", got, want)
+	}
+	if got := g.Unseen(0)[game.BlankLetter]; got+countBlanks(g.Rack(0)) != 4 {
+		t.Errorf("unseen blanks %d plus rack blanks %d, want 4", got, countBlanks(g.Rack(0)))
+	}
+}
+
+func countBlanks(rack []game.Tile) int {
+	blanks := 0
+	for _, tile := range rack {
+		if tile.IsBlank {
+			blanks++
+		}
+	}
+	return blanks
+}
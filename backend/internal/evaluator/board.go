@@ -0,0 +1,407 @@
+package evaluator
+
+import (
+	"math/rand"
+	"slices"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+)
+
+// Points a board feature is worth to whoever gets to use it next. The
+// Position, Defense and Volatility weights scale them like any other term.
+const (
+	tripleLaneValue = 8.0 // An empty triple-word square a play can now reach
+	sHookValue      = 3.0 // A square an S hooks onto
+	blankHookValue  = 1.0 // A square only some other letter hooks onto
+	bingoLaneValue  = 5.0 // A run of seven or more empty squares next to tiles
+
+	// replyShortlist is how many times topN moves EvaluateMoves rates with
+	// the reply estimate, after ranking all moves without it
+	replyShortlist = 3
+
+	bingoLength = 7
+)
+
+// boardView is the board moves are judged on
+type boardView struct {
+	before  *board.Board
+	after   *board.Board // before, plus the move being judged while it is judged
+	lexicon *gaddag.Compact
+	triples []game.Position // Triple-word squares or better
+
+	hookCache map[game.Position]hook // Hooks found on before
+
+	replyRacks  [][]game.Tile // Opponent racks to estimate replies with
+	replyBefore float64       // Mean best reply to replyRacks before the move
+}
+
+// SetBoard makes position, defense and volatility look at the board each
+// move would leave: triple-word squares opened, hooks for an S or blank,
+// and bingo lanes opened or closed. The board must not change while the
+// evaluator uses it, and an evaluator with a board is not safe for
+// concurrent use.
+func (e *Evaluator) SetBoard(b *board.Board, lexicon gaddag.Lexicon) {
+	e.view = &boardView{
+		before:    b,
+		after:     b.Clone(),
+		lexicon:   lexicon.Compact(),
+		hookCache: make(map[game.Position]hook),
+	}
+	for row := 0; row < b.Size(); row++ {
+		for col := 0; col < b.Size(); col++ {
+			if b.GetMultiplier(row, col).WordMultiplier() >= 3 {
+				e.view.triples = append(e.view.triples, game.Position{Row: row, Col: col})
+			}
+		}
+	}
+	e.SetReplySamples(0)
+}
+
+// SetReplySamples makes volatility include how much the move changes the
+// opponent's best reply score, averaged over n racks drawn from the
+// remaining tiles. This generates moves n times for every move rated, so
+// EvaluateMoves only applies it to a shortlist. It has no effect until
+// SetBoard is called; zero turns it off.
+func (e *Evaluator) SetReplySamples(n int) {
+	if e.view == nil {
+		return
+	}
+
+	e.view.replyRacks = nil
+	if n <= 0 {
+		return
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	remaining, _ := game.NewRackFromCounts(e.dist, e.remainingTiles)
+	pool := remaining.Tiles()
+	for i := 0; i < n && len(pool) > 0; i++ {
+		rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+		e.view.replyRacks = append(e.view.replyRacks, append([]game.Tile(nil), pool[:min(7, len(pool))]...))
+	}
+	e.view.replyBefore = e.view.meanReply(e.view.before, e.dist)
+}
+
+// boardTerms rates a placement on the board it leaves, in the units of the
+// position, defense and volatility terms
+func (e *Evaluator) boardTerms(move game.Move, withReply bool) (position, defense, volatility float64) {
+	v := e.view
+	affected := v.affectedSquares(move)
+	sHooksBefore, blankHooksBefore := v.hooks(v.before, affected, e.dist)
+
+	v.after.Place(move)
+	defer v.after.Unplace(move)
+
+	sHooks, blankHooks := v.hooks(v.after, affected, e.dist)
+
+	position = -tripleLaneValue * float64(v.triplesChange(move))
+	defense = -sHookValue*float64(sHooks-sHooksBefore) -
+		blankHookValue*float64(blankHooks-blankHooksBefore) -
+		bingoLaneValue*float64(v.lanesChange(move))
+	if withReply && len(v.replyRacks) > 0 {
+		volatility = v.replyBefore - v.meanReply(v.after, e.dist)
+	}
+	return position, defense, volatility
+}
+
+// meanReply averages the best score of each reply rack on b
+func (v *boardView) meanReply(b *board.Board, dist *game.LetterDistribution) float64 {
+	if len(v.replyRacks) == 0 {
+		return 0
+	}
+
+	gen := generator.NewWithDistribution(v.lexicon, b, dist)
+	total := 0
+	for _, rack := range v.replyRacks {
+		best := 0
+		gen.GenerateFunc(rack, func(move game.Move) bool {
+			best = max(best, move.Score)
+			return true
+		})
+		total += best
+	}
+	return float64(total) / float64(len(v.replyRacks))
+}
+
+// affectedSquares lists the squares whose hooks a move can change: those it
+// covers, and the empty squares at the ends of the words through them
+func (v *boardView) affectedSquares(move game.Move) []game.Position {
+	squares := make([]game.Position, 0, 4*len(move.TilesPlaced))
+	for _, pt := range move.TilesPlaced {
+		squares = append(squares, pt.Position)
+	}
+	occupied := func(row, col int) bool {
+		return v.before.GetTile(row, col) != nil || placesAt(move, row, col)
+	}
+
+	for _, pt := range move.TilesPlaced {
+		for _, step := range [4][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			row, col := pt.Position.Row+step[0], pt.Position.Col+step[1]
+			for v.before.InBounds(row, col) && occupied(row, col) {
+				row, col = row+step[0], col+step[1]
+			}
+			pos := game.Position{Row: row, Col: col}
+			if v.before.InBounds(row, col) && !slices.Contains(squares, pos) {
+				squares = append(squares, pos)
+			}
+		}
+	}
+	return squares
+}
+
+// placesAt reports whether a move puts a tile on (row, col)
+func placesAt(move game.Move, row, col int) bool {
+	for _, placed := range move.TilesPlaced {
+		if placed.Position.Row == row && placed.Position.Col == col {
+			return true
+		}
+	}
+	return false
+}
+
+// hook says which tiles can extend or join words from an empty square. S is
+// singled out as the letter that makes plurals in English, which the hook
+// values are tuned for; other distributions are judged by the same S, and
+// in one without an S every hook counts as a blank hook.
+type hook int8
+
+const (
+	noHook    hook = iota
+	blankHook      // Only letters other than S fit, so it takes a blank
+	sHook          // An S fits
+)
+
+// hooks counts the hooks among squares on b, caching those of the board
+// before the move as it does not change
+func (v *boardView) hooks(b *board.Board, squares []game.Position, dist *game.LetterDistribution) (sHooks, blankHooks int) {
+	for _, pos := range squares {
+		var h hook
+		if b == v.before {
+			cached, ok := v.hookCache[pos]
+			if !ok {
+				cached = v.hookAt(b, pos, dist)
+				v.hookCache[pos] = cached
+			}
+			h = cached
+		} else {
+			h = v.hookAt(b, pos, dist)
+		}
+
+		switch h {
+		case sHook:
+			sHooks++
+		case blankHook:
+			blankHooks++
+		}
+	}
+	return sHooks, blankHooks
+}
+
+// hookAt finds the kind of hook at a square on b
+func (v *boardView) hookAt(b *board.Board, pos game.Position, dist *game.LetterDistribution) hook {
+	if b.GetTile(pos.Row, pos.Col) != nil {
+		return noHook
+	}
+
+	h := noHook
+	for _, dir := range []game.Direction{game.Horizontal, game.Vertical} {
+		prefix, suffix := wordAround(b, pos, dir)
+		if len(prefix) == 0 && len(suffix) == 0 {
+			continue
+		}
+		for _, letter := range v.fitting(prefix, suffix, dist) {
+			if letter == 'S' {
+				return sHook
+			}
+			h = blankHook
+		}
+	}
+	return h
+}
+
+// wordAround returns the tiles before and after an empty square along dir,
+// with the prefix listed nearest first
+func wordAround(b *board.Board, pos game.Position, dir game.Direction) (prefix, suffix []rune) {
+	dr, dc := 0, 1
+	if dir == game.Vertical {
+		dr, dc = 1, 0
+	}
+
+	for row, col := pos.Row-dr, pos.Col-dc; b.GetTile(row, col) != nil; row, col = row-dr, col-dc {
+		prefix = append(prefix, b.GetTile(row, col).Letter)
+	}
+	for row, col := pos.Row+dr, pos.Col+dc; b.GetTile(row, col) != nil; row, col = row+dr, col+dc {
+		suffix = append(suffix, b.GetTile(row, col).Letter)
+	}
+	return prefix, suffix
+}
+
+// fitting returns the letters that make prefix + letter + suffix a word.
+// With a prefix, the GADDAG path splits after it, so the prefix is walked
+// once for all letters: the prefix reversed, the separator, the letter and
+// then the suffix.
+func (v *boardView) fitting(reversedPrefix, suffix []rune, dist *game.LetterDistribution) []rune {
+	lex := v.lexicon
+	follow := func(node uint32, letters []rune) (uint32, bool) {
+		ok := true
+		for _, l := range letters {
+			if node, ok = lex.Edge(node, l); !ok {
+				return 0, false
+			}
+		}
+		return node, true
+	}
+
+	var letters []rune
+	if len(reversedPrefix) > 0 {
+		node, ok := follow(lex.Root(), reversedPrefix)
+		if !ok {
+			return nil
+		}
+		if node, ok = lex.Edge(node, gaddag.Separator); !ok {
+			return nil
+		}
+		for _, letter := range dist.Letters {
+			if next, ok := lex.Edge(node, letter); ok {
+				if end, ok := follow(next, suffix); ok && lex.IsTerminal(end) {
+					letters = append(letters, letter)
+				}
+			}
+		}
+		return letters
+	}
+
+	// The word starts with the letter: letter, separator, suffix
+	for _, letter := range dist.Letters {
+		node, ok := lex.Edge(lex.Root(), letter)
+		if !ok {
+			continue
+		}
+		if node, ok = lex.Edge(node, gaddag.Separator); !ok {
+			continue
+		}
+		if end, ok := follow(node, suffix); ok && lex.IsTerminal(end) {
+			letters = append(letters, letter)
+		}
+	}
+	return letters
+}
+
+// triplesChange returns how many more triple-word squares are reachable
+// after a move than before. Only squares in line with a placed tile can change.
+func (v *boardView) triplesChange(move game.Move) int {
+	change := 0
+	for _, pos := range v.triples {
+		if !inLine(move, pos.Row, pos.Col) {
+			continue
+		}
+		if tripleReachable(v.after, pos.Row, pos.Col) {
+			change++
+		}
+		if tripleReachable(v.before, pos.Row, pos.Col) {
+			change--
+		}
+	}
+	return change
+}
+
+// tripleReachable reports whether a square is an empty triple-word square
+// (or better) that a play of up to seven tiles could cover, having a tile in
+// line with it with only empty squares between
+func tripleReachable(b *board.Board, row, col int) bool {
+	if b.GetTile(row, col) != nil || b.GetMultiplier(row, col).WordMultiplier() < 3 {
+		return false
+	}
+	for _, step := range [4][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+		if tileWithin(b, row, col, step, bingoLength) {
+			return true
+		}
+	}
+	return false
+}
+
+// inLine reports whether a square shares a row or column with a placed tile
+func inLine(move game.Move, row, col int) bool {
+	for _, placed := range move.TilesPlaced {
+		if placed.Position.Row == row || placed.Position.Col == col {
+			return true
+		}
+	}
+	return false
+}
+
+// tileWithin reports whether a tile lies within n squares of (row, col)
+// in the direction of step, past only empty squares
+func tileWithin(b *board.Board, row, col int, step [2]int, n int) bool {
+	for i := 1; i <= n; i++ {
+		row, col = row+step[0], col+step[1]
+		if !b.InBounds(row, col) {
+			return false
+		}
+		if b.GetTile(row, col) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// lanesChange returns how many more bingo lanes there are after a move than
+// before. Only the lines through a placed tile and those beside them can change.
+func (v *boardView) lanesChange(move game.Move) int {
+	size := v.before.Size()
+	rows, cols := make([]bool, size), make([]bool, size)
+	change := 0
+	for _, placed := range move.TilesPlaced {
+		for d := -1; d <= 1; d++ {
+			if row := placed.Position.Row + d; row >= 0 && row < size && !rows[row] {
+				rows[row] = true
+				change += lanesIn(v.after, row, false) - lanesIn(v.before, row, false)
+			}
+			if col := placed.Position.Col + d; col >= 0 && col < size && !cols[col] {
+				cols[col] = true
+				change += lanesIn(v.after, col, true) - lanesIn(v.before, col, true)
+			}
+		}
+	}
+	return change
+}
+
+// lanesIn counts the bingo lanes in one row, or one column if vertical
+func lanesIn(b *board.Board, line int, vertical bool) int {
+	at := func(i int) (int, int) {
+		if vertical {
+			return i, line
+		}
+		return line, i
+	}
+
+	count := 0
+	run, touches := 0, false
+	for i := 0; i <= b.Size(); i++ {
+		if i < b.Size() {
+			row, col := at(i)
+			if b.GetTile(row, col) == nil {
+				run++
+				touches = touches || touchesTile(b, row, col)
+				continue
+			}
+		}
+		if run >= bingoLength && touches {
+			count++
+		}
+		run, touches = 0, false
+	}
+	return count
+}
+
+// touchesTile reports whether a square has a tile beside, above or below it
+func touchesTile(b *board.Board, row, col int) bool {
+	for _, step := range [4][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+		if b.GetTile(row+step[0], col+step[1]) != nil {
+			return true
+		}
+	}
+	return false
+}
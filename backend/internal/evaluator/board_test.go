@@ -0,0 +1,190 @@
+package evaluator
+
+import (
+	"reflect"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+)
+
+func catBoard() *board.Board {
+	b := board.New()
+	for i, letter := range "CAT" {
+		b.SetTile(7, 7+i, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+	}
+	return b
+}
+
+func testLexicon() *gaddag.GADDAG {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "CATS", "SCAT", "AS", "AT", "TA", "TAS", "SAT"} {
+		g.Add(word)
+	}
+	return g
+}
+
+func reachableTriples(b *board.Board) int {
+	count := 0
+	for row := 0; row < b.Size(); row++ {
+		for col := 0; col < b.Size(); col++ {
+			if tripleReachable(b, row, col) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestTripleReachable(t *testing.T) {
+	b := board.New()
+	if n := reachableTriples(b); n != 0 {
+		t.Errorf("empty board: %d reachable triples, want 0", n)
+	}
+
+	// A tile on the centre reaches the four edge midpoints
+	b.SetTile(7, 7, &game.Tile{Letter: 'A', Value: 1})
+	if n := reachableTriples(b); n != 4 {
+		t.Errorf("centre tile: %d reachable triples, want 4", n)
+	}
+
+	// Covering (7,14) brings the corners on that edge in reach instead
+	b.SetTile(7, 14, &game.Tile{Letter: 'A', Value: 1})
+	if n := reachableTriples(b); n != 5 {
+		t.Errorf("with (7,14) covered: %d reachable triples, want 5", n)
+	}
+}
+
+func TestLanesIn(t *testing.T) {
+	b := catBoard()
+
+	// Row 7 has seven empty squares before CAT but only five after it.
+	// Column 8 is split by the A into two lanes of seven.
+	for _, tc := range []struct {
+		line     int
+		vertical bool
+		want     int
+	}{
+		{7, false, 1},
+		{6, false, 1},
+		{5, false, 0},
+		{8, true, 2},
+		{6, true, 1},
+	} {
+		if got := lanesIn(b, tc.line, tc.vertical); got != tc.want {
+			t.Errorf("lanesIn(%d, vertical=%v) = %d, want %d", tc.line, tc.vertical, got, tc.want)
+		}
+	}
+}
+
+func TestHooks(t *testing.T) {
+	e := New(map[rune]int{})
+	e.SetBoard(catBoard(), testLexicon())
+
+	// SCAT and CATS make both ends S hooks; above the A only TA fits
+	squares := []game.Position{{Row: 7, Col: 6}, {Row: 7, Col: 10}, {Row: 6, Col: 8}, {Row: 0, Col: 0}}
+	sHooks, blankHooks := e.view.hooks(e.view.before, squares, e.dist)
+	if sHooks != 2 || blankHooks != 1 {
+		t.Errorf("got %d S hooks and %d blank hooks, want 2 and 1", sHooks, blankHooks)
+	}
+}
+
+func TestBoardTerms(t *testing.T) {
+	b := board.New()
+	b.SetTile(7, 7, &game.Tile{Letter: 'A', Value: 1})
+	e := New(map[rune]int{})
+	e.SetBoard(b, testLexicon())
+
+	// Running out to (7,14) opens the two corners there but closes (7,14)
+	var placed []game.PlacedTile
+	for col := 8; col <= 14; col++ {
+		placed = append(placed, game.PlacedTile{Position: game.Position{Row: 7, Col: col}, Tile: game.Tile{Letter: 'E', Value: 1}})
+	}
+	position, _, _ := e.boardTerms(game.Move{TilesPlaced: placed}, false)
+	if want := -tripleLaneValue; position != want {
+		t.Errorf("position = %v, want %v", position, want)
+	}
+
+	// CATS covers one S hook, and SCATS is not a word to keep the other
+	e.SetBoard(catBoard(), testLexicon())
+	cats := game.Move{TilesPlaced: []game.PlacedTile{{Position: game.Position{Row: 7, Col: 10}, Tile: game.Tile{Letter: 'S', Value: 1}}}}
+	affected := e.view.affectedSquares(cats)
+	before, _ := e.view.hooks(e.view.before, affected, e.dist)
+	e.view.after.Place(cats)
+	after, _ := e.view.hooks(e.view.after, affected, e.dist)
+	e.view.after.Unplace(cats)
+	if before != 2 || after != 0 {
+		t.Errorf("S hooks around CATS: %d before and %d after, want 2 and 0", before, after)
+	}
+
+	if b.GetTile(7, 8) != nil || e.view.after.GetTile(7, 10) != nil {
+		t.Error("judging moves left tiles on the board")
+	}
+}
+
+func TestEvaluateMovesWithReplies(t *testing.T) {
+	b := catBoard()
+	remaining := map[rune]int{'A': 4, 'S': 2, 'T': 3, 'E': 4, 'Q': 1}
+	e := New(remaining)
+	e.SetBoard(b, testLexicon())
+	e.SetReplySamples(3)
+	if len(e.view.replyRacks) != 3 {
+		t.Fatalf("drew %d reply racks, want 3", len(e.view.replyRacks))
+	}
+
+	rack := []game.Tile{{Letter: 'S', Value: 1}, {Letter: 'A', Value: 1}}
+	moves := []game.Move{
+		{Word: "CATS", Position: game.Position{Row: 7, Col: 7}, Score: 6, TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 7, Col: 10}, Tile: game.Tile{Letter: 'S', Value: 1}},
+		}},
+		{Word: "SCAT", Position: game.Position{Row: 7, Col: 6}, Score: 6, TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 7, Col: 6}, Tile: game.Tile{Letter: 'S', Value: 1}},
+		}},
+		{Word: "AS", Position: game.Position{Row: 6, Col: 8}, Direction: game.Vertical, Score: 2, TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 6, Col: 8}, Tile: game.Tile{Letter: 'A', Value: 1}},
+		}},
+	}
+
+	best := e.EvaluateMoves(moves, rack, 2)
+	if len(best) != 2 {
+		t.Fatalf("got %d moves, want 2", len(best))
+	}
+	if again := e.EvaluateMoves(moves, rack, 2); !reflect.DeepEqual(again, best) {
+		t.Error("evaluating twice gave different rankings")
+	}
+	if b.GetTile(7, 10) != nil || b.GetTile(7, 6) != nil {
+		t.Error("evaluation changed the board")
+	}
+}
+
+func TestBestMovesWithReplies(t *testing.T) {
+	b := catBoard()
+	lexicon := testLexicon()
+	remaining := map[rune]int{'A': 4, 'S': 2, 'T': 3, 'E': 4, 'Q': 1}
+	e := New(remaining)
+	e.SetBoard(b, lexicon)
+	e.SetReplySamples(3)
+
+	rack := []game.Tile{{Letter: 'S', Value: 1}, {Letter: 'A', Value: 1}}
+	gen := generator.New(lexicon, b)
+	best := e.BestMoves(gen, rack, 0, 2)
+	if len(best) != 2 {
+		t.Fatalf("got %d moves, want 2", len(best))
+	}
+
+	// The leaders are rated with the reply estimate, as EvaluateMoves does
+	for _, r := range best {
+		if want := e.evaluateMove(r.Move, e.totalRemaining(), true); r.Value != want {
+			t.Errorf("%s rated %.2f, want %.2f with replies", r.Move.Word, r.Value, want)
+		}
+	}
+	if best[0].Value < best[1].Value {
+		t.Errorf("moves not best first: %.2f then %.2f", best[0].Value, best[1].Value)
+	}
+
+	// Streamed ratings leave the reply out
+	if _, value := e.Evaluate(best[0].Move, rack); value != e.evaluateMove(best[0].Move, e.totalRemaining(), false) {
+		t.Errorf("Evaluate rated %.2f, want the rating without replies", value)
+	}
+}
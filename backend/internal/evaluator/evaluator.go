@@ -4,6 +4,8 @@ import (
 	"math"
 	"sort"
 	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+	"unicode/utf8"
 )
 
 // Weights for different evaluation factors
@@ -60,58 +62,81 @@ var SynergyBonus = map[string]float64{
 type Evaluator struct {
 	weights        Weights
 	remainingTiles map[rune]int // Tiles left in bag
+	dist           *game.LetterDistribution
+	leaves         *LeaveTable // Replaces the hand-tuned leave values when set
+	view           *boardView  // The board moves are played to, when set
 }
 
 // New creates a new evaluator
 func New(remainingTiles map[rune]int) *Evaluator {
+	return NewWithWeights(remainingTiles, DefaultWeights)
+}
+
+// NewWithWeights creates an evaluator with custom weights
+func NewWithWeights(remainingTiles map[rune]int, weights Weights) *Evaluator {
 	return &Evaluator{
-		weights:        DefaultWeights,
+		weights:        weights,
 		remainingTiles: remainingTiles,
+		dist:           game.EnglishDistribution(),
 	}
 }
 
-// NewWithWeights creates an evaluator with custom weights
-func NewWithWeights(remainingTiles map[rune]int, weights Weights) *Evaluator {
+// NewWithDistribution creates an evaluator for a game played with the given
+// letter distribution, which sets the game stages and which tiles count as
+// high scoring
+func NewWithDistribution(remainingTiles map[rune]int, weights Weights, dist *game.LetterDistribution) *Evaluator {
 	return &Evaluator{
 		weights:        weights,
 		remainingTiles: remainingTiles,
+		dist:           dist,
 	}
 }
 
+// SetLeaves makes the evaluator value leaves from a table, such as one
+// trained by cmd/leavegen. Leaves the table lacks are estimated from their
+// sub-leaves in it, and only those it cannot estimate at all use the
+// hand-tuned values. Table values are already in points of equity, so they
+// count in full rather than being scaled by the Leave weight, except that a
+// zero weight, as in the endgame, still leaves them out.
+func (e *Evaluator) SetLeaves(leaves *LeaveTable) {
+	e.leaves = leaves
+}
+
 // EvaluateMoves takes scored moves and returns the best ones with full evaluation
 func (e *Evaluator) EvaluateMoves(moves []game.Move, rack []game.Tile, topN int) []game.Move {
 	if len(moves) == 0 {
 		return moves
 	}
 
-	// Calculate total remaining tiles
-	totalRemaining := 0
-	for _, count := range e.remainingTiles {
-		totalRemaining += count
-	}
-
 	// Evaluate each move
 	evaluatedMoves := make([]evaluatedMove, 0, len(moves))
 
+	totalRemaining := e.totalRemaining()
 	for _, move := range moves {
-		// Calculate leave tiles
-		leave := e.calculateLeave(rack, move.TilesPlaced)
-		move.Leave = leave
-
-		// Calculate evaluation score
-		evalScore := e.evaluateMove(move, totalRemaining)
+		move.Leave = e.leaveAfter(rack, move.TilesUsed())
 
 		evaluatedMoves = append(evaluatedMoves, evaluatedMove{
 			move:  move,
-			score: evalScore,
+			score: e.evaluateMove(move, totalRemaining, false),
 		})
 	}
 
-	// Sort by evaluation score
-	sort.Slice(evaluatedMoves, func(i, j int) bool {
+	// Sort by evaluation score, keeping the generator's order for ties
+	sort.SliceStable(evaluatedMoves, func(i, j int) bool {
 		return evaluatedMoves[i].score > evaluatedMoves[j].score
 	})
 
+	// Estimating replies is slow, so only the leaders are rated again with it
+	if e.view != nil && len(e.view.replyRacks) > 0 {
+		shortlist := evaluatedMoves[:min(len(evaluatedMoves), topN*replyShortlist)]
+		for i := range shortlist {
+			shortlist[i].score = e.evaluateMove(shortlist[i].move, totalRemaining, true)
+		}
+		sort.SliceStable(shortlist, func(i, j int) bool {
+			return shortlist[i].score > shortlist[j].score
+		})
+	}
+
 	// Return top N moves
 	result := make([]game.Move, 0, topN)
 	for i := 0; i < len(evaluatedMoves) && i < topN; i++ {
@@ -121,37 +146,100 @@ func (e *Evaluator) EvaluateMoves(moves []game.Move, rack []game.Tile, topN int)
 	return result
 }
 
+// Evaluate rates a single scored move played from rack, returning it with
+// its leave filled in. It lets callers rank moves as they are generated.
+// The reply estimate is left out, as it is too slow to apply to every move;
+// BestMoves and EvaluateMoves apply it to the leaders.
+func (e *Evaluator) Evaluate(move game.Move, rack []game.Tile) (game.Move, float64) {
+	// Calculate leave tiles
+	move.Leave = e.leaveAfter(rack, move.TilesUsed())
+
+	return move, e.evaluateMove(move, e.totalRemaining(), false)
+}
+
+// BestMoves generates every move from rack, rates each as it is found and
+// returns the k best with their ratings. Exchanges are included when the bag
+// holds enough tiles for one, and passing always is. As in EvaluateMoves,
+// the reply estimate is only applied to a shortlist of the leaders, which
+// are then ranked again. The generator must play to the board given to
+// SetBoard, if any.
+func (e *Evaluator) BestMoves(gen *generator.Generator, rack []game.Tile, bagLen, k int) []generator.RankedMove {
+	withReply := e.view != nil && len(e.view.replyRacks) > 0
+	shortlist := k
+	if withReply {
+		shortlist = k * replyShortlist
+	}
+
+	top := generator.NewTopK(shortlist)
+	gen.GenerateFunc(rack, func(move game.Move) bool {
+		top.Add(e.Evaluate(move, rack))
+		return true
+	})
+	for _, move := range append(gen.GenerateExchanges(rack, bagLen), game.Move{Kind: game.MovePass}) {
+		top.Add(e.Evaluate(move, rack))
+	}
+
+	ranked := top.Ranked()
+	if withReply {
+		totalRemaining := e.totalRemaining()
+		for i := range ranked {
+			ranked[i].Value = e.evaluateMove(ranked[i].Move, totalRemaining, true)
+		}
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return ranked[i].Value > ranked[j].Value
+		})
+	}
+	return ranked[:min(k, len(ranked))]
+}
+
+// totalRemaining counts the tiles left in the bag
+func (e *Evaluator) totalRemaining() int {
+	total := 0
+	for _, count := range e.remainingTiles {
+		total += count
+	}
+	return total
+}
+
 type evaluatedMove struct {
 	move  game.Move
 	score float64
 }
 
-// evaluateMove calculates the full evaluation score for a move
-func (e *Evaluator) evaluateMove(move game.Move, totalRemaining int) float64 {
+// evaluateMove calculates the full evaluation score for a move. withReply
+// includes the reply estimate, when SetReplySamples has turned it on.
+func (e *Evaluator) evaluateMove(move game.Move, totalRemaining int, withReply bool) float64 {
 	score := 0.0
 
 	// Adjust weights based on game stage
 	weights := e.adjustWeightsForGameStage(totalRemaining)
 
+	// Exchanges and passes score nothing and leave the board alone, so
+	// only what stays on the rack counts
+	if move.Kind != game.MovePlace {
+		return e.leaveTerm(move.Leave, weights)
+	}
+
 	// 1. Raw score component
 	score += float64(move.Score) * weights.Score
 
 	// 2. Leave evaluation
 	if totalRemaining > 0 { // No leave value in endgame
-		leaveValue := e.evaluateLeave(move.Leave)
-		score += leaveValue * weights.Leave
+		score += e.leaveTerm(move.Leave, weights)
 	}
 
-	// 3. Position evaluation
-	positionValue := e.evaluatePosition(move)
+	// 3-5. Position, defense and volatility, from the board the move
+	// leaves when we have one
+	var positionValue, defenseValue, volatilityValue float64
+	if e.view != nil {
+		positionValue, defenseValue, volatilityValue = e.boardTerms(move, withReply)
+	} else {
+		positionValue = e.evaluatePosition(move)
+		defenseValue = e.evaluateDefense(move)
+		volatilityValue = e.evaluateVolatility(move)
+	}
 	score += positionValue * weights.Position
-
-	// 4. Defensive evaluation (simplified for now)
-	defenseValue := e.evaluateDefense(move)
 	score += defenseValue * weights.Defense
-
-	// 5. Board volatility (simplified for now)
-	volatilityValue := e.evaluateVolatility(move)
 	score += volatilityValue * weights.Volatility
 
 	return score
@@ -172,7 +260,7 @@ func (e *Evaluator) adjustWeightsForGameStage(totalRemaining int) Weights {
 		// Pre-endgame: reduce leave importance
 		weights.Leave *= 0.5
 		weights.Score *= 1.2
-	} else if totalRemaining > 80 {
+	} else if totalRemaining > e.dist.TotalTiles()*4/5 {
 		// Early game: position and leave more important
 		weights.Position *= 1.3
 		weights.Leave *= 1.2
@@ -181,42 +269,37 @@ func (e *Evaluator) adjustWeightsForGameStage(totalRemaining int) Weights {
 	return weights
 }
 
-// calculateLeave determines which tiles remain after a move
+// calculateLeave determines which tiles remain after a placement
 func (e *Evaluator) calculateLeave(rack []game.Tile, tilesPlaced []game.PlacedTile) []game.Tile {
-	// Create a map of placed tiles for easy lookup
-	placedMap := make(map[rune]int)
-	blanksPlaced := 0
-
-	for _, placed := range tilesPlaced {
-		if placed.Tile.IsBlank {
-			blanksPlaced++
-		} else {
-			placedMap[placed.Tile.Letter]++
-		}
-	}
+	return e.leaveAfter(rack, game.Move{TilesPlaced: tilesPlaced}.TilesUsed())
+}
 
-	// Calculate remaining tiles
-	var leave []game.Tile
-	blanksLeft := 0
+// leaveAfter determines which tiles remain after a move uses some, in
+// distribution order with blanks last
+func (e *Evaluator) leaveAfter(rack []game.Tile, used []game.Tile) []game.Tile {
+	leave, _ := game.NewRack(e.dist, rack)
+	for _, tile := range used {
+		// A placed blank uses up any blank on the rack
+		leave.Remove(tile)
+	}
+	return leave.Tiles()
+}
 
-	for _, tile := range rack {
-		if tile.IsBlank {
-			if blanksLeft < blanksPlaced {
-				blanksLeft++
-				continue
-			}
-			leave = append(leave, tile)
-		} else if placedMap[tile.Letter] > 0 {
-			placedMap[tile.Letter]--
-		} else {
-			leave = append(leave, tile)
+// leaveTerm is what keeping leave adds to a move's value: its table value
+// when the table has one, otherwise the hand-tuned value scaled by weight
+func (e *Evaluator) leaveTerm(leave []game.Tile, weights Weights) float64 {
+	if weights.Leave == 0 || len(leave) == 0 {
+		return 0
+	}
+	if e.leaves != nil {
+		if value, ok := e.leaves.Estimate(leave, e.dist); ok {
+			return value
 		}
 	}
-
-	return leave
+	return e.evaluateLeave(leave) * weights.Leave
 }
 
-// evaluateLeave calculates the value of remaining tiles
+// evaluateLeave calculates the hand-tuned value of remaining tiles
 func (e *Evaluator) evaluateLeave(leave []game.Tile) float64 {
 	if len(leave) == 0 {
 		return 0
@@ -298,7 +381,7 @@ func (e *Evaluator) calculateSynergies(leave []game.Tile) float64 {
 	return bonus
 }
 
-// evaluatePosition calculates position value of a move
+// evaluatePosition calculates position value of a move without a board
 func (e *Evaluator) evaluatePosition(move game.Move) float64 {
 	value := 0.0
 
@@ -307,9 +390,9 @@ func (e *Evaluator) evaluatePosition(move game.Move) float64 {
 	centerCol := move.Position.Col
 
 	if move.Direction == game.Horizontal {
-		centerCol += len(move.Word) / 2
+		centerCol += utf8.RuneCountInString(move.Word) / 2
 	} else {
-		centerRow += len(move.Word) / 2
+		centerRow += utf8.RuneCountInString(move.Word) / 2
 	}
 
 	// Distance from center (7,7) - closer is better in early game
@@ -325,18 +408,18 @@ func (e *Evaluator) evaluatePosition(move game.Move) float64 {
 	return value
 }
 
-// evaluateDefense calculates defensive value (simplified)
+// evaluateDefense calculates defensive value without a board
 func (e *Evaluator) evaluateDefense(move game.Move) float64 {
 	// Simple heuristic: longer words are more defensive (block more squares)
-	return float64(len(move.Word)) * 0.5
+	return float64(utf8.RuneCountInString(move.Word)) * 0.5
 }
 
-// evaluateVolatility calculates board volatility impact (simplified)
+// evaluateVolatility calculates board volatility impact without a board
 func (e *Evaluator) evaluateVolatility(move game.Move) float64 {
 	// High-scoring tiles placed are less volatile
 	highValueTiles := 0
 	for _, placed := range move.TilesPlaced {
-		if placed.Tile.Value >= 4 {
+		if !placed.Tile.IsBlank && e.dist.Value(placed.Tile.Letter) >= 4 {
 			highValueTiles++
 		}
 	}
@@ -240,6 +240,40 @@ func TestEvaluateMoves(t *testing.T) {
 	}
 }
 
+func TestExchangeRankedOnLeave(t *testing.T) {
+	eval := New(map[rune]int{'E': 8, 'A': 6, 'I': 6, 'O': 5, 'S': 4, 'R': 5, 'T': 5, 'N': 5})
+
+	dist := game.EnglishDistribution()
+	var rack []game.Tile
+	for _, letter := range "QVVWEST" {
+		rack = append(rack, dist.Tile(letter))
+	}
+
+	weak := game.Move{
+		Word:      "TE",
+		Position:  game.Position{Row: 7, Col: 7},
+		Direction: game.Horizontal,
+		Score:     4,
+		TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 7, Col: 7}, Tile: dist.Tile('T')},
+			{Position: game.Position{Row: 7, Col: 8}, Tile: dist.Tile('E')},
+		},
+	}
+	exchange := game.Move{
+		Kind:      game.MoveExchange,
+		Exchanged: []game.Tile{dist.Tile('Q'), dist.Tile('V'), dist.Tile('V'), dist.Tile('W')},
+	}
+	pass := game.Move{Kind: game.MovePass}
+
+	evaluated := eval.EvaluateMoves([]game.Move{weak, pass, exchange}, rack, 3)
+	if evaluated[0].Kind != game.MoveExchange {
+		t.Fatalf("best move is kind %d, want the exchange", evaluated[0].Kind)
+	}
+	if got := len(evaluated[0].Leave); got != 3 {
+		t.Errorf("exchange leaves %d tiles, want 3", got)
+	}
+}
+
 func TestGameStageAdjustment(t *testing.T) {
 	eval := New(map[rune]int{})
 
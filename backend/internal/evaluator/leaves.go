@@ -0,0 +1,173 @@
+package evaluator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"tiletactics/backend/internal/game"
+)
+
+// LeaveTable holds the value in points of keeping each leave, keyed by the
+// leave's tiles in distribution order with blanks last, such as "EIST?"
+type LeaveTable struct {
+	values map[string]float64
+}
+
+// NewLeaveTable creates an empty leave table
+func NewLeaveTable() *LeaveTable {
+	return &LeaveTable{values: make(map[string]float64)}
+}
+
+// LeaveKey returns the table key for a leave
+func LeaveKey(leave []game.Tile, dist *game.LetterDistribution) string {
+	rack, _ := game.NewRack(dist, leave)
+	return rack.String()
+}
+
+// LoadLeaves reads a leave table file
+func LoadLeaves(filename string) (*LeaveTable, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open leave file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseLeaves(file)
+}
+
+// ParseLeaves reads a leave table with one leave and its value per line:
+//
+//	# leave value
+//	S 7.85
+//	EIST? 31.2
+//
+// Blank lines and lines starting with '#' are ignored.
+func ParseLeaves(r io.Reader) (*LeaveTable, error) {
+	t := NewLeaveTable()
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("leave file line %d: want leave and value", lineNum)
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("leave file line %d: bad value %q", lineNum, fields[1])
+		}
+		t.values[fields[0]] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading leave file: %w", err)
+	}
+
+	return t, nil
+}
+
+// WriteTo writes the table in the format read by ParseLeaves, shortest
+// leaves first
+func (t *LeaveTable) WriteTo(w io.Writer) (int64, error) {
+	keys := make([]string, 0, len(t.values))
+	for key := range t.values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	bw := bufio.NewWriter(w)
+	var written int64
+	for _, key := range keys {
+		n, err := fmt.Fprintf(bw, "%s %.3f\n", key, t.values[key])
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, bw.Flush()
+}
+
+// Set records the value of a leave by its key
+func (t *LeaveTable) Set(key string, value float64) {
+	t.values[key] = value
+}
+
+// Get returns the value of a leave by its key
+func (t *LeaveTable) Get(key string) (float64, bool) {
+	value, ok := t.values[key]
+	return value, ok
+}
+
+// Len returns the number of leaves in the table
+func (t *LeaveTable) Len() int {
+	return len(t.values)
+}
+
+// Estimate returns the value of a leave, backing off to its sub-leaves when
+// the table lacks it: each way of splitting off one tile gives the value of
+// the rest plus that of the tile alone, and the estimate is their average.
+// It reports false when no split can be valued from the table.
+func (t *LeaveTable) Estimate(leave []game.Tile, dist *game.LetterDistribution) (float64, bool) {
+	return t.estimate(leave, dist, make(map[string]estimate))
+}
+
+type estimate struct {
+	value float64
+	ok    bool
+}
+
+// estimate is Estimate, remembering the sub-leaves already worked out
+func (t *LeaveTable) estimate(leave []game.Tile, dist *game.LetterDistribution, seen map[string]estimate) (float64, bool) {
+	key := LeaveKey(leave, dist)
+	if value, ok := t.values[key]; ok {
+		return value, true
+	}
+	if len(leave) < 2 {
+		return 0, false
+	}
+	if e, ok := seen[key]; ok {
+		return e.value, e.ok
+	}
+
+	total, n := 0.0, 0
+	tried := make(map[string]bool)
+	for i := range leave {
+		single := LeaveKey(leave[i:i+1], dist)
+		if tried[single] {
+			continue
+		}
+		tried[single] = true
+
+		tileValue, ok := t.values[single]
+		if !ok {
+			continue
+		}
+		rest := append(append([]game.Tile(nil), leave[:i]...), leave[i+1:]...)
+		if restValue, ok := t.estimate(rest, dist, seen); ok {
+			total += restValue + tileValue
+			n++
+		}
+	}
+
+	e := estimate{ok: n > 0}
+	if e.ok {
+		e.value = total / float64(n)
+	}
+	seen[key] = e
+	return e.value, e.ok
+}
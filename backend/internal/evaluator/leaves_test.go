@@ -0,0 +1,110 @@
+package evaluator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"tiletactics/backend/internal/game"
+)
+
+func TestLeaveTableRoundTrip(t *testing.T) {
+	table, err := ParseLeaves(strings.NewReader("# leave value\nS 7.5\n\nEIST? 31.25\nQ -7\n"))
+	if err != nil {
+		t.Fatalf("ParseLeaves() error = %v", err)
+	}
+	if table.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", table.Len())
+	}
+
+	var buf bytes.Buffer
+	if _, err := table.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if want := "Q -7.000\nS 7.500\nEIST? 31.250\n"; buf.String() != want {
+		t.Errorf("WriteTo() wrote %q, want %q", buf.String(), want)
+	}
+
+	if _, err := ParseLeaves(strings.NewReader("S seven\n")); err == nil {
+		t.Error("ParseLeaves() accepted a bad value")
+	}
+}
+
+func TestEvaluatorUsesLeaveTable(t *testing.T) {
+	dist := game.EnglishDistribution()
+	leave := []game.Tile{dist.Tile(game.BlankLetter), dist.Tile('S'), dist.Tile('E')}
+	if key := LeaveKey(leave, dist); key != "ES?" {
+		t.Fatalf("LeaveKey() = %q, want ES?", key)
+	}
+
+	table := NewLeaveTable()
+	table.Set("ES?", 99)
+
+	eval := New(map[rune]int{'A': 50})
+	eval.SetLeaves(table)
+
+	// Table values count in full; leaves it cannot value are hand-tuned and weighted
+	if got := eval.leaveTerm(leave, DefaultWeights); got != 99 {
+		t.Errorf("leaveTerm() with table = %v, want 99", got)
+	}
+	want := eval.evaluateLeave(leave[1:]) * DefaultWeights.Leave
+	if got := eval.leaveTerm(leave[1:], DefaultWeights); got != want {
+		t.Errorf("leaveTerm() of a leave missing from the table = %v, want the hand-tuned %v", got, want)
+	}
+}
+
+func TestEquityWithLeaveTable(t *testing.T) {
+	dist := game.EnglishDistribution()
+	table := NewLeaveTable()
+	table.Set("ES?", 20)
+
+	// Only score and leave count, in the middle of the game
+	eval := NewWithDistribution(map[rune]int{'A': 50}, Weights{Score: 1, Leave: DefaultWeights.Leave}, dist)
+	eval.SetLeaves(table)
+
+	rack := []game.Tile{dist.Tile('A'), dist.Tile('T'), dist.Tile('E'), dist.Tile('S'), dist.Tile(game.BlankLetter)}
+	move := game.Move{
+		Kind:      game.MovePlace,
+		Word:      "AT",
+		Position:  game.Position{Row: 7, Col: 7},
+		Direction: game.Horizontal,
+		TilesPlaced: []game.PlacedTile{
+			{Tile: dist.Tile('A'), Position: game.Position{Row: 7, Col: 7}},
+			{Tile: dist.Tile('T'), Position: game.Position{Row: 7, Col: 8}},
+		},
+		Score: 4,
+	}
+
+	if _, value := eval.Evaluate(move, rack); value != 24 {
+		t.Errorf("Evaluate() = %v, want score 4 plus leave 20", value)
+	}
+}
+
+func TestEstimateBacksOffToSubLeaves(t *testing.T) {
+	dist := game.EnglishDistribution()
+	table, err := ParseLeaves(strings.NewReader("E 2\nS 8\nES 12\nQ -7\n"))
+	if err != nil {
+		t.Fatalf("ParseLeaves() error = %v", err)
+	}
+
+	tests := []struct {
+		leave string
+		want  float64
+		ok    bool
+	}{
+		{"ES", 12, true},
+		{"EQ", -5, true},  // E + Q
+		{"EES", 13, true}, // (ES + E, and EE + S with EE as E + E) averaged
+		{"Z", 0, false},
+		{"ZZ", 0, false},
+	}
+	for _, tt := range tests {
+		var leave []game.Tile
+		for _, letter := range tt.leave {
+			leave = append(leave, dist.Tile(letter))
+		}
+		got, ok := table.Estimate(leave, dist)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Estimate(%s) = %v, %v, want %v, %v", tt.leave, got, ok, tt.want, tt.ok)
+		}
+	}
+}
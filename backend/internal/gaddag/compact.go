@@ -0,0 +1,311 @@
+package gaddag
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// compactMagic and compactVersion identify a serialised Compact GADDAG
+const (
+	compactMagic   = "TTGD"
+	compactVersion = 1
+)
+
+// ErrBadCompact is returned when reading data that is not a compact GADDAG
+var ErrBadCompact = errors.New("not a compact GADDAG file")
+
+// Compact is a read-only GADDAG stored as flat arrays with equivalent
+// subtrees shared, as in a DAWG. Nodes are numbered from 0, the root; the
+// edges of node n are letters[offsets[n]:offsets[n+1]], sorted, leading to
+// the matching entries of targets.
+type Compact struct {
+	offsets  []uint32
+	letters  []rune
+	targets  []uint32
+	terminal []uint64 // One bit per node
+}
+
+// Lexicon is a dictionary that can be searched for words and walked as a
+// Compact GADDAG. Both GADDAG and Compact satisfy it.
+type Lexicon interface {
+	Contains(word string) bool
+	Compact() *Compact
+}
+
+// Root returns the root node
+func (c *Compact) Root() uint32 {
+	return 0
+}
+
+// Edge returns the node reached from node by letter
+func (c *Compact) Edge(node uint32, letter rune) (uint32, bool) {
+	lo, hi := c.offsets[node], c.offsets[node+1]
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case c.letters[mid] == letter:
+			return c.targets[mid], true
+		case c.letters[mid] < letter:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return 0, false
+}
+
+// IsTerminal returns true if a word ends at node
+func (c *Compact) IsTerminal(node uint32) bool {
+	return c.terminal[node/64]&(1<<(node%64)) != 0
+}
+
+// NodeCount returns the number of distinct nodes
+func (c *Compact) NodeCount() int {
+	return len(c.offsets) - 1
+}
+
+// EdgeCount returns the number of edges
+func (c *Compact) EdgeCount() int {
+	return len(c.letters)
+}
+
+// Compact returns c itself, so a Compact can be used as a Lexicon
+func (c *Compact) Compact() *Compact {
+	return c
+}
+
+// Contains reports whether word is in the dictionary, following the path
+// for the word's first letter: reversed prefix, separator, then suffix
+func (c *Compact) Contains(word string) bool {
+	runes := []rune(strings.ToUpper(word))
+	if len(runes) == 0 {
+		return false
+	}
+
+	node, ok := c.Edge(c.Root(), runes[0])
+	if !ok {
+		return false
+	}
+	if len(runes) > 1 {
+		if node, ok = c.Edge(node, Separator); !ok {
+			return false
+		}
+		for _, letter := range runes[1:] {
+			if node, ok = c.Edge(node, letter); !ok {
+				return false
+			}
+		}
+	}
+	return c.IsTerminal(node)
+}
+
+func (c *Compact) Stats() string {
+	return fmt.Sprintf("Compact GADDAG Stats: %d nodes, %d edges", c.NodeCount(), c.EdgeCount())
+}
+
+// Compact returns the dictionary as a Compact GADDAG. The result is cached
+// until another word is added.
+func (g *GADDAG) Compact() *Compact {
+	if g.compact == nil {
+		g.compact = newCompact(g.root)
+	}
+	return g.compact
+}
+
+// newCompact flattens the trie rooted at root, merging nodes that have the
+// same terminal flag and the same edges to already merged children
+func newCompact(root *Node) *Compact {
+	b := &compactBuilder{
+		ids:      make(map[*Node]uint32),
+		register: make(map[string]uint32),
+	}
+	b.visit(root)
+
+	// Nodes were numbered children first, so the root came last; reverse
+	// the numbering so the root is node 0
+	last := uint32(len(b.nodes) - 1)
+	c := &Compact{
+		offsets:  make([]uint32, 0, len(b.nodes)+1),
+		terminal: make([]uint64, (len(b.nodes)+63)/64),
+	}
+	for id := range b.nodes {
+		n := b.nodes[last-uint32(id)]
+		c.offsets = append(c.offsets, uint32(len(c.letters)))
+		if n.terminal {
+			c.terminal[id/64] |= 1 << (id % 64)
+		}
+		for _, e := range n.edges {
+			c.letters = append(c.letters, e.letter)
+			c.targets = append(c.targets, last-e.target)
+		}
+	}
+	c.offsets = append(c.offsets, uint32(len(c.letters)))
+
+	return c
+}
+
+type compactEdge struct {
+	letter rune
+	target uint32
+}
+
+type compactNode struct {
+	terminal bool
+	edges    []compactEdge
+}
+
+type compactBuilder struct {
+	ids      map[*Node]uint32  // Merged id of each visited trie node
+	register map[string]uint32 // Merged id of each distinct node signature
+	nodes    []compactNode
+}
+
+// visit returns the merged id of node, numbering its children first
+func (b *compactBuilder) visit(node *Node) uint32 {
+	if id, ok := b.ids[node]; ok {
+		return id
+	}
+
+	n := compactNode{terminal: node.terminal, edges: make([]compactEdge, 0, len(node.edges))}
+	for letter, child := range node.edges {
+		n.edges = append(n.edges, compactEdge{letter: letter, target: b.visit(child)})
+	}
+	sort.Slice(n.edges, func(i, j int) bool {
+		return n.edges[i].letter < n.edges[j].letter
+	})
+
+	key := n.signature()
+	id, ok := b.register[key]
+	if !ok {
+		id = uint32(len(b.nodes))
+		b.nodes = append(b.nodes, n)
+		b.register[key] = id
+	}
+	b.ids[node] = id
+	return id
+}
+
+// signature identifies a node by its terminal flag and edges
+func (n compactNode) signature() string {
+	buf := make([]byte, 1, 1+8*len(n.edges))
+	if n.terminal {
+		buf[0] = 1
+	}
+	for _, e := range n.edges {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(e.letter))
+		buf = binary.LittleEndian.AppendUint32(buf, e.target)
+	}
+	return string(buf)
+}
+
+// WriteTo writes the GADDAG in a little-endian binary form read by ReadCompact:
+// a header of magic, version, node and edge counts, followed by the offsets,
+// letters, targets and terminal bitset arrays
+func (c *Compact) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+
+	header := []uint32{compactVersion, uint32(c.NodeCount()), uint32(c.EdgeCount())}
+	if _, err := cw.Write([]byte(compactMagic)); err != nil {
+		return cw.n, err
+	}
+	for _, data := range []any{header, c.offsets, c.letters, c.targets, c.terminal} {
+		if err := binary.Write(cw, binary.LittleEndian, data); err != nil {
+			return cw.n, fmt.Errorf("failed to write compact GADDAG: %w", err)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, fmt.Errorf("failed to write compact GADDAG: %w", err)
+	}
+	return cw.n, nil
+}
+
+// ReadCompact reads a GADDAG written by Compact.WriteTo
+func ReadCompact(r io.Reader) (*Compact, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(compactMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != compactMagic {
+		return nil, ErrBadCompact
+	}
+
+	var header [3]uint32
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadCompact, err)
+	}
+	if header[0] != compactVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrBadCompact, header[0])
+	}
+	nodes, edges := header[1], header[2]
+	if nodes == 0 {
+		return nil, fmt.Errorf("%w: no nodes", ErrBadCompact)
+	}
+
+	c := &Compact{
+		offsets:  make([]uint32, nodes+1),
+		letters:  make([]rune, edges),
+		targets:  make([]uint32, edges),
+		terminal: make([]uint64, (nodes+63)/64),
+	}
+	for _, data := range []any{c.offsets, c.letters, c.targets, c.terminal} {
+		if err := binary.Read(br, binary.LittleEndian, data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBadCompact, err)
+		}
+	}
+
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadCompact reads a compact GADDAG file
+func LoadCompact(filename string) (*Compact, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open compact GADDAG: %w", err)
+	}
+	defer file.Close()
+
+	return ReadCompact(file)
+}
+
+// check makes sure every offset and target is in range, so a corrupt file
+// cannot make lookups panic
+func (c *Compact) check() error {
+	edges := uint32(len(c.letters))
+	nodes := uint32(c.NodeCount())
+
+	for i := 1; i < len(c.offsets); i++ {
+		if c.offsets[i] < c.offsets[i-1] || c.offsets[i] > edges {
+			return fmt.Errorf("%w: bad offset for node %d", ErrBadCompact, i-1)
+		}
+	}
+	if c.offsets[0] != 0 || c.offsets[nodes] != edges {
+		return fmt.Errorf("%w: offsets do not cover the edges", ErrBadCompact)
+	}
+	for i, target := range c.targets {
+		if target >= nodes {
+			return fmt.Errorf("%w: edge %d points past the last node", ErrBadCompact, i)
+		}
+	}
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
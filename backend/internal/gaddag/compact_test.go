@@ -0,0 +1,125 @@
+package gaddag
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var compactWords = []string{"CAT", "CATS", "AT", "ATE", "SCAT", "BAT", "BATS", "A", "EAT", "EATS"}
+
+func newCompactTestGADDAG() *GADDAG {
+	g := New()
+	for _, word := range compactWords {
+		g.Add(word)
+	}
+	return g
+}
+
+func TestCompactMatchesGADDAG(t *testing.T) {
+	g := newCompactTestGADDAG()
+	c := g.Compact()
+
+	for _, word := range append(compactWords, "CA", "TAC", "BATE", "S", "", "CATSS") {
+		if got, want := c.Contains(word), g.Contains(word); got != want {
+			t.Errorf("Compact.Contains(%q) = %v, GADDAG.Contains = %v", word, got, want)
+		}
+	}
+
+	nodes, edges := g.countNodesAndEdges()
+	if c.NodeCount() >= nodes || c.EdgeCount() >= edges {
+		t.Errorf("Compact has %d nodes and %d edges, want fewer than the trie's %d and %d",
+			c.NodeCount(), c.EdgeCount(), nodes, edges)
+	}
+}
+
+func TestCompactPathsMatchNodes(t *testing.T) {
+	g := newCompactTestGADDAG()
+	c := g.Compact()
+
+	// Walk both forms along every path the trie has
+	var walk func(n *Node, id uint32, path string)
+	walk = func(n *Node, id uint32, path string) {
+		if n.IsTerminal() != c.IsTerminal(id) {
+			t.Errorf("path %q: terminal %v, compact %v", path, n.IsTerminal(), c.IsTerminal(id))
+		}
+		for letter, child := range n.edges {
+			next, ok := c.Edge(id, letter)
+			if !ok {
+				t.Errorf("path %q: compact is missing edge %c", path, letter)
+				continue
+			}
+			walk(child, next, path+string(letter))
+		}
+	}
+	walk(g.Root(), c.Root(), "")
+
+	if _, ok := c.Edge(c.Root(), 'Z'); ok {
+		t.Error("compact has an edge for Z from the root")
+	}
+}
+
+func TestCompactCacheResetOnAdd(t *testing.T) {
+	g := newCompactTestGADDAG()
+	if g.Compact().Contains("DOG") {
+		t.Fatal("DOG found before it was added")
+	}
+
+	g.Add("DOG")
+	if !g.Compact().Contains("DOG") {
+		t.Error("Compact() not rebuilt after Add")
+	}
+}
+
+func TestCompactRoundTrip(t *testing.T) {
+	c := newCompactTestGADDAG().Compact()
+
+	var buf bytes.Buffer
+	n, err := c.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d bytes, wrote %d", n, buf.Len())
+	}
+
+	read, err := ReadCompact(&buf)
+	if err != nil {
+		t.Fatalf("ReadCompact() error = %v", err)
+	}
+	if read.NodeCount() != c.NodeCount() || read.EdgeCount() != c.EdgeCount() {
+		t.Errorf("read %s, want %s", read.Stats(), c.Stats())
+	}
+	for _, word := range compactWords {
+		if !read.Contains(word) {
+			t.Errorf("read GADDAG is missing %q", word)
+		}
+	}
+}
+
+func TestReadCompactRejectsBadData(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := newCompactTestGADDAG().Compact().WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// A single node whose only edge points past the end
+	var corrupt bytes.Buffer
+	bad := &Compact{offsets: []uint32{0, 1}, letters: []rune{'A'}, targets: []uint32{5}, terminal: []uint64{0}}
+	if _, err := bad.WriteTo(&corrupt); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]byte{
+		"Empty":       nil,
+		"Wrong magic": append([]byte("NOPE"), data[4:]...),
+		"Truncated":   data[:len(data)-4],
+		"Bad target":  corrupt.Bytes(),
+	}
+	for name, input := range tests {
+		if _, err := ReadCompact(bytes.NewReader(input)); !errors.Is(err, ErrBadCompact) {
+			t.Errorf("%s: ReadCompact() error = %v, want ErrBadCompact", name, err)
+		}
+	}
+}
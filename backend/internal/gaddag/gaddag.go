@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"tiletactics/backend/internal/game"
 )
 
 const Separator = '>'
@@ -34,7 +35,9 @@ func (n *Node) IsTerminal() bool {
 }
 
 type GADDAG struct {
-	root *Node
+	root      *Node
+	compact   *Compact // Built on demand by Compact
+	minimized bool     // Nodes may be shared between words
 }
 
 func New() *GADDAG {
@@ -51,6 +54,11 @@ func (g *GADDAG) Add(word string) {
 	if len(word) == 0 {
 		return
 	}
+	g.compact = nil
+	if g.minimized {
+		// The root may have been merged with an equivalent node
+		g.root = copyNode(g.root)
+	}
 
 	word = strings.ToUpper(word)
 	runes := []rune(word)
@@ -60,28 +68,17 @@ func (g *GADDAG) Add(word string) {
 
 		// Add reversed prefix
 		for j := i - 1; j >= 0; j-- {
-			letter := runes[j]
-			if current.edges[letter] == nil {
-				current.edges[letter] = NewNode()
-			}
-			current = current.edges[letter]
+			current = g.edge(current, runes[j])
 		}
 
 		// Add separator for non-empty prefix
 		if i > 0 {
-			if current.edges[Separator] == nil {
-				current.edges[Separator] = NewNode()
-			}
-			current = current.edges[Separator]
+			current = g.edge(current, Separator)
 		}
 
 		// Add suffix
 		for j := i; j < len(runes); j++ {
-			letter := runes[j]
-			if current.edges[letter] == nil {
-				current.edges[letter] = NewNode()
-			}
-			current = current.edges[letter]
+			current = g.edge(current, runes[j])
 		}
 
 		current.terminal = true
@@ -153,6 +150,36 @@ func LoadFromFile(filename string) (*GADDAG, error) {
 	return gaddag, nil
 }
 
+// LoadWithAlphabet loads a dictionary whose words are spelled with the
+// alphabet's tiles, so that a digraph such as CH is stored as one edge.
+// Words that cannot be spelled with the alphabet are skipped.
+func LoadWithAlphabet(filename string, alphabet *game.Alphabet) (*GADDAG, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary file: %w", err)
+	}
+	defer file.Close()
+
+	gaddag := New()
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" {
+			continue
+		}
+		if encoded, err := alphabet.Encode(word); err == nil {
+			gaddag.Add(encoded)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading dictionary file: %w", err)
+	}
+
+	return gaddag, nil
+}
+
 func (g *GADDAG) Stats() string {
 	nodeCount, edgeCount := g.countNodesAndEdges()
 	return fmt.Sprintf("GADDAG Stats: %d nodes, %d edges", nodeCount, edgeCount)
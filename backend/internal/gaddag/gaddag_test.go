@@ -1,7 +1,10 @@
 package gaddag
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"tiletactics/backend/internal/game"
 )
 
 func TestGADDAGBasic(t *testing.T) {
@@ -93,3 +96,34 @@ func TestLoadFromFile(t *testing.T) {
 
 	t.Logf("Dictionary stats: %s", g.Stats())
 }
+
+func TestLoadWithAlphabet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spanish.txt")
+	if err := os.WriteFile(path, []byte("CHE\nAÑO\nZETA\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	alphabet, err := game.NewAlphabet([]string{"A", "C", "CH", "E", "H", "N", "Ñ", "O"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := LoadWithAlphabet(path, alphabet)
+	if err != nil {
+		t.Fatalf("LoadWithAlphabet() error = %v", err)
+	}
+
+	che, _ := alphabet.Encode("CHE")
+	if !g.Contains(che) || len([]rune(che)) != 2 {
+		t.Errorf("CHE not stored as two tiles")
+	}
+	if !g.Contains("AÑO") {
+		t.Errorf("Contains(AÑO) = false, want true")
+	}
+	if g.Contains("CHE") {
+		t.Errorf("CHE stored as three single letters")
+	}
+	if g.Contains("ZETA") {
+		t.Errorf("ZETA cannot be spelled with the alphabet but was stored")
+	}
+}
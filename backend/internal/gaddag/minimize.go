@@ -0,0 +1,82 @@
+package gaddag
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// Minimize merges equivalent nodes, those with the same terminal flag and
+// the same edges to the same children, so shared suffixes are stored once.
+// Words can still be added afterwards; Add copies any node it changes.
+func (g *GADDAG) Minimize() {
+	m := &minimizer{
+		ids:      make(map[*Node]uint32),
+		register: make(map[string]*Node),
+	}
+	g.root = m.merge(g.root)
+	g.minimized = true
+	g.compact = nil
+}
+
+type minimizer struct {
+	ids      map[*Node]uint32 // Id of each canonical node
+	register map[string]*Node // Canonical node for each signature
+}
+
+// merge returns the canonical node equivalent to node, merging its children first
+func (m *minimizer) merge(node *Node) *Node {
+	if _, ok := m.ids[node]; ok {
+		return node
+	}
+
+	letters := make([]rune, 0, len(node.edges))
+	for letter, child := range node.edges {
+		node.edges[letter] = m.merge(child)
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i] < letters[j]
+	})
+
+	key := make([]byte, 1, 1+8*len(letters))
+	if node.terminal {
+		key[0] = 1
+	}
+	for _, letter := range letters {
+		key = binary.LittleEndian.AppendUint32(key, uint32(letter))
+		key = binary.LittleEndian.AppendUint32(key, m.ids[node.edges[letter]])
+	}
+
+	if canonical, ok := m.register[string(key)]; ok {
+		return canonical
+	}
+	m.register[string(key)] = node
+	m.ids[node] = uint32(len(m.ids))
+	return node
+}
+
+// edge returns the child of current for letter, creating it if needed. Once
+// the GADDAG has been minimised a child may be shared by other words, so
+// it is copied before being handed back for changes.
+func (g *GADDAG) edge(current *Node, letter rune) *Node {
+	child := current.edges[letter]
+	switch {
+	case child == nil:
+		child = NewNode()
+	case g.minimized:
+		child = copyNode(child)
+	default:
+		return child
+	}
+	current.edges[letter] = child
+	return child
+}
+
+// copyNode returns a node with the same edges and terminal flag as n
+func copyNode(n *Node) *Node {
+	copied := &Node{edges: make(map[rune]*Node, len(n.edges)), terminal: n.terminal}
+	for letter, child := range n.edges {
+		copied.edges[letter] = child
+	}
+	return copied
+}
@@ -0,0 +1,115 @@
+package gaddag
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// allWords lists every word by following the paths from the root that
+// spell a whole word with no separator
+func allWords(g *GADDAG) []string {
+	var words []string
+	var walk func(n *Node, prefix string)
+	walk = func(n *Node, prefix string) {
+		if n.IsTerminal() && prefix != "" {
+			words = append(words, prefix)
+		}
+		for letter, child := range n.edges {
+			if letter != Separator {
+				walk(child, prefix+string(letter))
+			}
+		}
+	}
+	walk(g.Root(), "")
+	sort.Strings(words)
+	return words
+}
+
+func TestMinimizePreservesWords(t *testing.T) {
+	words := []string{"CAT", "CATS", "BAT", "BATS", "RAT", "RATS", "AT", "ATE", "TEA", "EAT", "EATS", "SEAT"}
+
+	g := New()
+	for _, word := range words {
+		g.Add(word)
+	}
+	before := allWords(g)
+	nodesBefore, edgesBefore := g.countNodesAndEdges()
+
+	g.Minimize()
+
+	if after := allWords(g); !reflect.DeepEqual(after, before) {
+		t.Errorf("words after Minimize() = %v, want %v", after, before)
+	}
+	for _, word := range append(words, "CA", "TAC", "BATE", "SEATS", "A") {
+		want := false
+		for _, w := range words {
+			want = want || w == word
+		}
+		if got := g.Contains(word); got != want {
+			t.Errorf("Contains(%q) = %v after Minimize(), want %v", word, got, want)
+		}
+	}
+
+	nodesAfter, edgesAfter := g.countNodesAndEdges()
+	if nodesAfter >= nodesBefore || edgesAfter >= edgesBefore {
+		t.Errorf("Minimize() left %d nodes and %d edges, want fewer than %d and %d",
+			nodesAfter, edgesAfter, nodesBefore, edgesBefore)
+	}
+	t.Logf("%d nodes, %d edges -> %d nodes, %d edges", nodesBefore, edgesBefore, nodesAfter, edgesAfter)
+}
+
+func TestMinimizeSharesSuffixes(t *testing.T) {
+	g := New()
+	g.Add("CATS")
+	g.Add("BATS")
+	g.Minimize()
+
+	// After C and B the rest of each word, "ATS", is the same path
+	fromC := g.Root().GetEdge('C').GetEdge(Separator)
+	fromB := g.Root().GetEdge('B').GetEdge(Separator)
+	if fromC == nil || fromC != fromB {
+		t.Errorf("C> and B> lead to different nodes: %p and %p", fromC, fromB)
+	}
+}
+
+func TestAddAfterMinimize(t *testing.T) {
+	g := New()
+	g.Add("CATS")
+	g.Add("BATS")
+	g.Minimize()
+
+	// BAT shares nodes with CATS; adding it must not make CAT a word
+	g.Add("BAT")
+
+	for word, want := range map[string]bool{"CATS": true, "BATS": true, "BAT": true, "CAT": false} {
+		if got := g.Contains(word); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", word, got, want)
+		}
+	}
+	if got, want := allWords(g), []string{"BAT", "BATS", "CATS"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("words = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkMinimizeNWL2023(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		g, err := LoadFromFile("../../dictionaries/NWL2023.txt")
+		if err != nil {
+			b.Skipf("Skipping benchmark: %v", err)
+		}
+		nodes, edges := g.countNodesAndEdges()
+		b.StartTimer()
+
+		g.Minimize()
+
+		b.StopTimer()
+		minNodes, minEdges := g.countNodesAndEdges()
+		b.ReportMetric(float64(nodes), "nodes-before")
+		b.ReportMetric(float64(minNodes), "nodes-after")
+		b.ReportMetric(float64(edges), "edges-before")
+		b.ReportMetric(float64(minEdges), "edges-after")
+		b.StartTimer()
+	}
+}
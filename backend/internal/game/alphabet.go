@@ -0,0 +1,146 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// firstDigraphCode is where codes for multi-character tiles start. Tiles
+// are runes everywhere else, so a digraph such as Spanish CH is given a
+// code from the Unicode private use area that no dictionary word contains.
+const firstDigraphCode = '\uE000'
+
+// Alphabet maps the tile codes used by the board, GADDAG and generator to
+// the strings shown to players. Single-character tiles, including accented
+// letters such as Ñ, are their own code.
+type Alphabet struct {
+	letters []rune
+	display map[rune]string
+	codes   map[string]rune
+	longest int // Longest symbol, in runes
+}
+
+// NewAlphabet creates an alphabet from tile symbols such as "A", "Ñ" or "CH"
+func NewAlphabet(symbols []string) (*Alphabet, error) {
+	a := &Alphabet{
+		display: make(map[rune]string),
+		codes:   make(map[string]rune),
+	}
+
+	next := firstDigraphCode
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if _, exists := a.codes[symbol]; exists {
+			return nil, fmt.Errorf("tile %s listed twice", symbol)
+		}
+
+		var code rune
+		switch n := utf8.RuneCountInString(symbol); {
+		case n == 0:
+			return nil, fmt.Errorf("empty tile symbol")
+		case n == 1:
+			code, _ = utf8.DecodeRuneInString(symbol)
+			if code == BlankLetter || code == '_' {
+				return nil, fmt.Errorf("%c is reserved for blanks", code)
+			}
+		default:
+			code = next
+			next++
+		}
+
+		a.letters = append(a.letters, code)
+		a.display[code] = symbol
+		a.codes[symbol] = code
+		if n := utf8.RuneCountInString(symbol); n > a.longest {
+			a.longest = n
+		}
+	}
+
+	return a, nil
+}
+
+// EnglishAlphabet returns the letters A to Z
+func EnglishAlphabet() *Alphabet {
+	return EnglishDistribution().Alphabet()
+}
+
+// Letters returns every tile code in the alphabet's order
+func (a *Alphabet) Letters() []rune {
+	return a.letters
+}
+
+// Contains reports whether a code is a tile in the alphabet
+func (a *Alphabet) Contains(code rune) bool {
+	_, ok := a.display[code]
+	return ok
+}
+
+// Display returns the string shown for a tile code. Codes outside the
+// alphabet are shown as themselves.
+func (a *Alphabet) Display(code rune) string {
+	if symbol, ok := a.display[code]; ok {
+		return symbol
+	}
+	return string(code)
+}
+
+// Code returns the tile code for a symbol, in either case
+func (a *Alphabet) Code(symbol string) (rune, bool) {
+	code, ok := a.codes[strings.ToUpper(symbol)]
+	return code, ok
+}
+
+// Encode converts a word as written, e.g. "CHILE", into tile codes. Symbols
+// are matched longest first; wrap a symbol in brackets to choose a different
+// split, e.g. "[C][H]" for C followed by H.
+func (a *Alphabet) Encode(word string) (string, error) {
+	runes := []rune(strings.ToUpper(word))
+
+	var encoded strings.Builder
+	for i := 0; i < len(runes); {
+		if runes[i] == '[' {
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return "", fmt.Errorf("unclosed bracket in %q", word)
+			}
+			code, ok := a.codes[string(runes[i+1:end])]
+			if !ok {
+				return "", fmt.Errorf("unknown tile %q in %q", string(runes[i+1:end]), word)
+			}
+			encoded.WriteRune(code)
+			i = end + 1
+			continue
+		}
+
+		matched := false
+		for n := a.longest; n > 0; n-- {
+			if i+n > len(runes) {
+				continue
+			}
+			if code, ok := a.codes[string(runes[i:i+n])]; ok {
+				encoded.WriteRune(code)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "", fmt.Errorf("unknown tile %q in %q", string(runes[i]), word)
+		}
+	}
+
+	return encoded.String(), nil
+}
+
+// Decode converts a word of tile codes back into display form
+func (a *Alphabet) Decode(word string) string {
+	var decoded strings.Builder
+	for _, code := range word {
+		decoded.WriteString(a.Display(code))
+	}
+	return decoded.String()
+}
@@ -0,0 +1,102 @@
+package game
+
+import "testing"
+
+func newSpanishAlphabet(t *testing.T) *Alphabet {
+	t.Helper()
+
+	a, err := NewAlphabet([]string{"A", "C", "CH", "E", "H", "I", "L", "LL", "N", "Ñ", "O", "R", "RR"})
+	if err != nil {
+		t.Fatalf("NewAlphabet() error = %v", err)
+	}
+	return a
+}
+
+func TestAlphabetCodes(t *testing.T) {
+	a := newSpanishAlphabet(t)
+
+	if code, ok := a.Code("ñ"); !ok || code != 'Ñ' {
+		t.Errorf("Code(ñ) = %q, %v, want Ñ", code, ok)
+	}
+
+	ch, ok := a.Code("ch")
+	if !ok || ch < firstDigraphCode {
+		t.Fatalf("Code(ch) = %q, %v, want a private use code", ch, ok)
+	}
+	if got := a.Display(ch); got != "CH" {
+		t.Errorf("Display(CH code) = %q, want CH", got)
+	}
+	if !a.Contains(ch) || a.Contains('Z') {
+		t.Errorf("Contains() wrong for CH or Z")
+	}
+
+	if _, err := NewAlphabet([]string{"A", "?"}); err == nil {
+		t.Error("NewAlphabet() accepted the blank symbol")
+	}
+}
+
+func TestAlphabetEncode(t *testing.T) {
+	a := newSpanishAlphabet(t)
+
+	tests := []struct {
+		word  string
+		tiles int
+	}{
+		{"CHILE", 4},   // CH I L E
+		{"CALLE", 4},   // C A LL E
+		{"CORRER", 5},  // C O RR E R
+		{"AÑO", 3},     // Ñ is one tile
+		{"[C][H]E", 3}, // Brackets split the digraph
+		{"cerro", 4},   // Lowercase is accepted
+	}
+
+	for _, tt := range tests {
+		encoded, err := a.Encode(tt.word)
+		if err != nil {
+			t.Errorf("Encode(%q) error = %v", tt.word, err)
+			continue
+		}
+		if got := len([]rune(encoded)); got != tt.tiles {
+			t.Errorf("Encode(%q) gave %d tiles, want %d", tt.word, got, tt.tiles)
+		}
+	}
+
+	for _, word := range []string{"ZAPATO", "[CH"} {
+		if _, err := a.Encode(word); err == nil {
+			t.Errorf("Encode(%q) succeeded, want error", word)
+		}
+	}
+}
+
+func TestAlphabetDecodeRoundTrip(t *testing.T) {
+	a := newSpanishAlphabet(t)
+
+	for _, word := range []string{"CHILE", "CALLE", "AÑO", "CORRER"} {
+		encoded, err := a.Encode(word)
+		if err != nil {
+			t.Fatalf("Encode(%q) error = %v", word, err)
+		}
+		if got := a.Decode(encoded); got != word {
+			t.Errorf("Decode(Encode(%q)) = %q", word, got)
+		}
+	}
+}
+
+func TestSpanishDistribution(t *testing.T) {
+	d, err := LoadDistribution("../../distributions/spanish.txt")
+	if err != nil {
+		t.Fatalf("LoadDistribution() error = %v", err)
+	}
+
+	if got := d.TotalTiles(); got != 100 {
+		t.Errorf("TotalTiles() = %d, want 100", got)
+	}
+
+	rr, ok := d.Alphabet().Code("RR")
+	if !ok || d.Value(rr) != 8 || d.Count(rr) != 1 {
+		t.Errorf("RR: code %q ok %v value %d count %d, want 8 points and 1 tile", rr, ok, d.Value(rr), d.Count(rr))
+	}
+	if d.Value('Ñ') != 8 {
+		t.Errorf("Value(Ñ) = %d, want 8", d.Value('Ñ'))
+	}
+}
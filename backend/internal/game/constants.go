@@ -1,5 +1,7 @@
 package game
 
+// BoardSize is the size of the standard board; boards with other layouts
+// report their own size through board.Size
 const BoardSize = 15
 
 // TileValues maps each letter to its point value
@@ -21,3 +23,6 @@ var TileDistribution = map[rune]int{
 	'U': 4, 'V': 2, 'W': 2, 'X': 1, 'Y': 2,
 	'Z': 1, '_': 2, // '_' represents blanks
 }
+
+// BlankLetter represents an undesignated blank tile on a rack or in the bag
+const BlankLetter = '?'
@@ -0,0 +1,194 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LetterDistribution describes the tile set of a lexicon or language: which
+// letters exist, how many of each are in the bag and what they score
+type LetterDistribution struct {
+	Name     string
+	Letters  []rune // Tile code of every letter in the set, in file order
+	Counts   map[rune]int
+	Values   map[rune]int
+	Blanks   int
+	alphabet *Alphabet
+	index    map[rune]int // Position of each letter in Letters
+}
+
+// MaxLetters is the most letters a distribution may have; racks and the
+// generator's cross-checks keep one bit or counter per letter
+const MaxLetters = 64
+
+// EnglishDistribution returns the standard 100-tile English set
+func EnglishDistribution() *LetterDistribution {
+	d := &LetterDistribution{
+		Name:   "english",
+		Counts: make(map[rune]int),
+		Values: make(map[rune]int),
+		Blanks: TileDistribution['_'],
+	}
+
+	for letter, value := range TileValues {
+		d.Letters = append(d.Letters, letter)
+		d.Counts[letter] = TileDistribution[letter]
+		d.Values[letter] = value
+	}
+	sort.Slice(d.Letters, func(i, j int) bool {
+		return d.Letters[i] < d.Letters[j]
+	})
+
+	symbols := make([]string, len(d.Letters))
+	for i, letter := range d.Letters {
+		symbols[i] = string(letter)
+	}
+	d.alphabet, _ = NewAlphabet(symbols)
+	d.indexLetters()
+
+	return d
+}
+
+// LoadDistribution reads a distribution file, named after the file
+func LoadDistribution(filename string) (*LetterDistribution, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open distribution file: %w", err)
+	}
+	defer file.Close()
+
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return ParseDistribution(name, file)
+}
+
+// ParseDistribution reads a distribution with one tile per line:
+//
+//	# letter count value
+//	A 12 1
+//	CH 1 5
+//	? 2 0
+//
+// where '?' is the blank. A tile may be several characters long, such as a
+// Spanish digraph. Blank lines and lines starting with '#' are ignored.
+func ParseDistribution(name string, r io.Reader) (*LetterDistribution, error) {
+	d := &LetterDistribution{
+		Name:   name,
+		Counts: make(map[rune]int),
+		Values: make(map[rune]int),
+	}
+
+	var symbols []string
+	var counts, values []int
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("distribution %s line %d: want letter, count and value", name, lineNum)
+		}
+
+		count, err := strconv.Atoi(fields[1])
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("distribution %s line %d: bad count %q", name, lineNum, fields[1])
+		}
+		value, err := strconv.Atoi(fields[2])
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("distribution %s line %d: bad value %q", name, lineNum, fields[2])
+		}
+
+		if fields[0] == string(BlankLetter) {
+			d.Blanks = count
+			continue
+		}
+
+		symbols = append(symbols, fields[0])
+		counts = append(counts, count)
+		values = append(values, value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading distribution %s: %w", name, err)
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("distribution %s has no letters", name)
+	}
+	if len(symbols) > MaxLetters {
+		return nil, fmt.Errorf("distribution %s has %d letters, more than %d", name, len(symbols), MaxLetters)
+	}
+
+	alphabet, err := NewAlphabet(symbols)
+	if err != nil {
+		return nil, fmt.Errorf("distribution %s: %w", name, err)
+	}
+
+	d.alphabet = alphabet
+	d.Letters = alphabet.Letters()
+	for i, letter := range d.Letters {
+		d.Counts[letter] = counts[i]
+		d.Values[letter] = values[i]
+	}
+	d.indexLetters()
+
+	return d, nil
+}
+
+func (d *LetterDistribution) indexLetters() {
+	d.index = make(map[rune]int, len(d.Letters))
+	for i, letter := range d.Letters {
+		d.index[letter] = i
+	}
+}
+
+// Index returns the position of a letter in Letters
+func (d *LetterDistribution) Index(letter rune) (int, bool) {
+	i, ok := d.index[letter]
+	return i, ok
+}
+
+// Alphabet returns the tiles of the distribution and how they are displayed
+func (d *LetterDistribution) Alphabet() *Alphabet {
+	return d.alphabet
+}
+
+// Value returns the score of a letter; blanks and unknown letters score nothing
+func (d *LetterDistribution) Value(letter rune) int {
+	return d.Values[letter]
+}
+
+// Count returns how many tiles of a letter are in a full bag, with '?' for blanks
+func (d *LetterDistribution) Count(letter rune) int {
+	if letter == BlankLetter {
+		return d.Blanks
+	}
+	return d.Counts[letter]
+}
+
+// TotalTiles returns the number of tiles in a full bag
+func (d *LetterDistribution) TotalTiles() int {
+	total := d.Blanks
+	for _, count := range d.Counts {
+		total += count
+	}
+	return total
+}
+
+// Tile returns a rack tile for a letter, with '?' giving an undesignated blank
+func (d *LetterDistribution) Tile(letter rune) Tile {
+	if letter == BlankLetter {
+		return Tile{Letter: BlankLetter, Value: 0, IsBlank: true}
+	}
+	return Tile{Letter: letter, Value: d.Value(letter)}
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnglishDistribution(t *testing.T) {
+	d := EnglishDistribution()
+
+	if got := d.TotalTiles(); got != 100 {
+		t.Errorf("TotalTiles() = %d, want 100", got)
+	}
+	if len(d.Letters) != 26 || d.Letters[0] != 'A' || d.Letters[25] != 'Z' {
+		t.Errorf("Letters = %q, want A to Z", string(d.Letters))
+	}
+	if d.Value('Q') != 10 || d.Count('E') != 12 || d.Count(BlankLetter) != 2 {
+		t.Errorf("Q value %d, E count %d, blanks %d", d.Value('Q'), d.Count('E'), d.Count(BlankLetter))
+	}
+}
+
+func TestParseDistribution(t *testing.T) {
+	d, err := ParseDistribution("house", strings.NewReader("# house rules\nA 3 2\n\nb 1 5\n? 1 0\n"))
+	if err != nil {
+		t.Fatalf("ParseDistribution() error = %v", err)
+	}
+
+	if string(d.Letters) != "AB" {
+		t.Errorf("Letters = %q, want AB", string(d.Letters))
+	}
+	if d.Value('A') != 2 || d.Value('B') != 5 || d.TotalTiles() != 5 {
+		t.Errorf("A=%d B=%d total=%d, want 2, 5 and 5", d.Value('A'), d.Value('B'), d.TotalTiles())
+	}
+	if tile := d.Tile(BlankLetter); !tile.IsBlank || tile.Value != 0 {
+		t.Errorf("Tile('?') = %+v, want a blank", tile)
+	}
+}
+
+func TestParseDistributionErrors(t *testing.T) {
+	tests := map[string]string{
+		"Missing value":  "A 9\n",
+		"Bad count":      "A x 1\n",
+		"Negative value": "A 9 -1\n",
+		"Duplicate":      "A 9 1\nA 1 1\n",
+		"No letters":     "? 2 0\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseDistribution("bad", strings.NewReader(input)); err == nil {
+				t.Errorf("ParseDistribution(%q) succeeded, want error", input)
+			}
+		})
+	}
+}
+
+func TestLoadDistributionFiles(t *testing.T) {
+	tests := []struct {
+		file  string
+		total int
+	}{
+		{"../../distributions/english.txt", 100},
+		{"../../distributions/super.txt", 200},
+	}
+
+	english := EnglishDistribution()
+	for _, tt := range tests {
+		d, err := LoadDistribution(tt.file)
+		if err != nil {
+			t.Fatalf("LoadDistribution(%s) error = %v", tt.file, err)
+		}
+		if got := d.TotalTiles(); got != tt.total {
+			t.Errorf("%s: TotalTiles() = %d, want %d", d.Name, got, tt.total)
+		}
+		for _, letter := range english.Letters {
+			if d.Value(letter) != english.Value(letter) {
+				t.Errorf("%s: %c worth %d, want %d", d.Name, letter, d.Value(letter), english.Value(letter))
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Moves are written in tournament notation: a coordinate, then the word.
+// A coordinate starting with the row number, such as 8H, is a play across;
+// one starting with the column letter, such as H8, is a play down. Rows
+// count from 1 and columns from A. In the word, letters already on the
+// board are in parentheses and blanks are lower case: 8G Q(U)IXOTIc. An
+// exchange is written -ABC and a pass -.
+
+// Coordinate returns where a placement starts, such as 8H or H8
+func (m Move) Coordinate() string {
+	col := string(rune('A' + m.Position.Col))
+	row := strconv.Itoa(m.Position.Row + 1)
+	if m.Direction == Vertical {
+		return col + row
+	}
+	return row + col
+}
+
+// Notation returns the move in tournament notation, spelling tiles as the
+// alphabet displays them
+func (m Move) Notation(alphabet *Alphabet) string {
+	return m.notation(alphabet, false)
+}
+
+// DottedNotation returns the move as Notation does, but with '.' for each
+// letter already on the board, as .gcg files write it: 8G Q.IXOTIc
+func (m Move) DottedNotation(alphabet *Alphabet) string {
+	return m.notation(alphabet, true)
+}
+
+func (m Move) notation(alphabet *Alphabet, dotted bool) string {
+	switch m.Kind {
+	case MoveExchange:
+		var sb strings.Builder
+		sb.WriteByte('-')
+		for _, tile := range m.Exchanged {
+			if tile.IsBlank {
+				sb.WriteRune(BlankLetter)
+			} else {
+				sb.WriteString(alphabet.Display(tile.Letter))
+			}
+		}
+		return sb.String()
+	case MovePass:
+		return "-"
+	}
+
+	placed := make(map[Position]Tile, len(m.TilesPlaced))
+	for _, p := range m.TilesPlaced {
+		placed[p.Position] = p.Tile
+	}
+
+	var sb strings.Builder
+	sb.WriteString(m.Coordinate())
+	sb.WriteByte(' ')
+	pos := m.Position
+	through := false
+	for _, letter := range m.Word {
+		tile, ok := placed[pos]
+		if !ok && dotted {
+			sb.WriteByte('.')
+			pos = advance(pos, m.Direction, 1)
+			continue
+		}
+		if ok == through {
+			// Open or close a run of letters already on the board
+			if through {
+				sb.WriteByte(')')
+			} else {
+				sb.WriteByte('(')
+			}
+			through = !through
+		}
+
+		symbol := alphabet.Display(letter)
+		if ok && tile.IsBlank {
+			symbol = strings.ToLower(symbol)
+		}
+		sb.WriteString(symbol)
+		pos = advance(pos, m.Direction, 1)
+	}
+	if through {
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
+
+// ParseCoordinate reads a coordinate such as 8H (across) or H8 (down)
+func ParseCoordinate(coord string) (Position, Direction, error) {
+	coord = strings.ToUpper(strings.TrimSpace(coord))
+	digits := strings.IndexFunc(coord, unicode.IsDigit)
+	letters := strings.IndexFunc(coord, unicode.IsLetter)
+	if digits < 0 || letters < 0 {
+		return Position{}, 0, fmt.Errorf("bad coordinate %q", coord)
+	}
+
+	dir := Horizontal
+	rowText, colText := coord[:letters], coord[letters:]
+	if letters == 0 {
+		dir = Vertical
+		rowText, colText = coord[digits:], coord[:digits]
+	}
+
+	row, err := strconv.Atoi(rowText)
+	if err != nil || row < 1 || len(colText) != 1 || colText[0] < 'A' || colText[0] > 'Z' {
+		return Position{}, 0, fmt.Errorf("bad coordinate %q", coord)
+	}
+	return Position{Row: row - 1, Col: int(colText[0] - 'A')}, dir, nil
+}
+
+// ParseMove reads a move in tournament notation. The coordinate and word
+// may be separated by a space or a colon, as in 8H:QUIXOTIC. Letters
+// outside parentheses are taken to be placed, so a play through tiles
+// already on the board must put them in parentheses. Placed tiles score
+// as the distribution says; the move's Score is left for the scorer.
+func ParseMove(notation string, dist *LetterDistribution) (Move, error) {
+	notation = strings.TrimSpace(notation)
+	if notation == "-" || strings.EqualFold(notation, "pass") {
+		return Move{Kind: MovePass}, nil
+	}
+	if tiles, ok := strings.CutPrefix(notation, "-"); ok {
+		return parseExchange(tiles, dist)
+	}
+
+	coord, word, ok := strings.Cut(notation, " ")
+	if !ok {
+		coord, word, ok = strings.Cut(notation, ":")
+	}
+	word = strings.TrimSpace(word)
+	if !ok || word == "" {
+		return Move{}, fmt.Errorf("move %q needs a coordinate and a word", notation)
+	}
+
+	pos, dir, err := ParseCoordinate(coord)
+	if err != nil {
+		return Move{}, err
+	}
+	move := Move{Kind: MovePlace, Position: pos, Direction: dir}
+
+	var letters strings.Builder
+	for i, part := range strings.Split(word, "(") {
+		placedPart, throughPart := part, ""
+		if i > 0 {
+			var closed bool
+			throughPart, placedPart, closed = strings.Cut(part, ")")
+			if !closed || throughPart == "" {
+				return Move{}, fmt.Errorf("unbalanced parentheses in %q", word)
+			}
+		}
+		if strings.Contains(placedPart, ")") {
+			return Move{}, fmt.Errorf("unbalanced parentheses in %q", word)
+		}
+
+		codes, err := dist.Alphabet().Encode(throughPart)
+		if err != nil {
+			return Move{}, err
+		}
+		letters.WriteString(codes)
+		pos = advance(pos, dir, utf8.RuneCountInString(codes))
+
+		tiles, err := parseTiles(placedPart, dist)
+		if err != nil {
+			return Move{}, err
+		}
+		for _, tile := range tiles {
+			move.TilesPlaced = append(move.TilesPlaced, PlacedTile{Position: pos, Tile: tile})
+			letters.WriteRune(tile.Letter)
+			pos = advance(pos, dir, 1)
+		}
+	}
+
+	if len(move.TilesPlaced) == 0 {
+		return Move{}, fmt.Errorf("move %q places no tiles", notation)
+	}
+	move.Word = letters.String()
+	return move, nil
+}
+
+// parseExchange reads the tiles after the '-' of an exchange
+func parseExchange(tiles string, dist *LetterDistribution) (Move, error) {
+	exchanged, err := ParseRack(tiles, dist)
+	if err != nil {
+		return Move{}, err
+	}
+	return Move{Kind: MoveExchange, Exchanged: exchanged}, nil
+}
+
+// ParseRack reads tiles written as the alphabet displays them, in either
+// case, with '?' for blanks
+func ParseRack(tiles string, dist *LetterDistribution) ([]Tile, error) {
+	var rack []Tile
+	for i, part := range strings.Split(tiles, string(BlankLetter)) {
+		if i > 0 {
+			rack = append(rack, dist.Tile(BlankLetter))
+		}
+		codes, err := dist.Alphabet().Encode(part)
+		if err != nil {
+			return nil, err
+		}
+		for _, code := range codes {
+			rack = append(rack, dist.Tile(code))
+		}
+	}
+	return rack, nil
+}
+
+// parseTiles reads placed tiles, a lower-case symbol being a blank
+func parseTiles(word string, dist *LetterDistribution) ([]Tile, error) {
+	alphabet := dist.Alphabet()
+	codes, err := alphabet.Encode(word)
+	if err != nil {
+		return nil, err
+	}
+
+	// Line each code up with the symbol it came from to see its case
+	written := []rune(strings.NewReplacer("[", "", "]", "").Replace(word))
+	tiles := make([]Tile, 0, len(written))
+	for _, code := range codes {
+		tile := Tile{Letter: code, IsBlank: unicode.IsLower(written[0])}
+		if !tile.IsBlank {
+			tile.Value = dist.Value(code)
+		}
+		tiles = append(tiles, tile)
+		written = written[utf8.RuneCountInString(alphabet.Display(code)):]
+	}
+	return tiles, nil
+}
+
+// advance moves a position n squares in a direction
+func advance(pos Position, dir Direction, n int) Position {
+	if dir == Horizontal {
+		pos.Col += n
+	} else {
+		pos.Row += n
+	}
+	return pos
+}
@@ -0,0 +1,144 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	tests := []struct {
+		coord string
+		pos   Position
+		dir   Direction
+	}{
+		{"8H", Position{Row: 7, Col: 7}, Horizontal},
+		{"H8", Position{Row: 7, Col: 7}, Vertical},
+		{"15a", Position{Row: 14, Col: 0}, Horizontal},
+		{"O1", Position{Row: 0, Col: 14}, Vertical},
+	}
+	for _, tt := range tests {
+		pos, dir, err := ParseCoordinate(tt.coord)
+		if err != nil || pos != tt.pos || dir != tt.dir {
+			t.Errorf("ParseCoordinate(%q) = %v, %v, %v, want %v, %v", tt.coord, pos, dir, err, tt.pos, tt.dir)
+		}
+	}
+
+	for _, coord := range []string{"", "8", "H", "0H", "8HH", "8-"} {
+		if _, _, err := ParseCoordinate(coord); err == nil {
+			t.Errorf("ParseCoordinate(%q) succeeded, want error", coord)
+		}
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	d := EnglishDistribution()
+
+	move, err := ParseMove("8G Q(U)IXOTIc", d)
+	if err != nil {
+		t.Fatalf("ParseMove() error = %v", err)
+	}
+	if move.Word != "QUIXOTIC" || move.Position != (Position{Row: 7, Col: 6}) || move.Direction != Horizontal {
+		t.Errorf("parsed %s at %v going %v", move.Word, move.Position, move.Direction)
+	}
+	if len(move.TilesPlaced) != 7 {
+		t.Fatalf("placed %d tiles, want 7", len(move.TilesPlaced))
+	}
+	if first := move.TilesPlaced[0]; first.Position != (Position{Row: 7, Col: 6}) || first.Tile.Value != 10 {
+		t.Errorf("first tile = %+v, want Q worth 10 at 8G", first)
+	}
+	if second := move.TilesPlaced[1]; second.Position != (Position{Row: 7, Col: 8}) {
+		t.Errorf("second tile at %v, want 8I after the U on the board", second.Position)
+	}
+	if last := move.TilesPlaced[6].Tile; !last.IsBlank || last.Letter != 'C' || last.Value != 0 {
+		t.Errorf("last tile = %+v, want a blank C", last)
+	}
+
+	down, err := ParseMove("h8:cat", d)
+	if err != nil || down.Direction != Vertical || down.TilesPlaced[2].Position != (Position{Row: 9, Col: 7}) {
+		t.Errorf("ParseMove(h8:cat) = %+v, %v", down, err)
+	}
+
+	exchange, err := ParseMove("-QU?", d)
+	if err != nil || exchange.Kind != MoveExchange || len(exchange.Exchanged) != 3 || !exchange.Exchanged[2].IsBlank {
+		t.Errorf("ParseMove(-QU?) = %+v, %v", exchange, err)
+	}
+	if pass, err := ParseMove("-", d); err != nil || pass.Kind != MovePass {
+		t.Errorf("ParseMove(-) = %+v, %v", pass, err)
+	}
+
+	for _, bad := range []string{"8H", "CAT", "8H (CAT)", "8H CA(T", "8H C)AT", "8H C1T", "-Q1"} {
+		if _, err := ParseMove(bad, d); err == nil {
+			t.Errorf("ParseMove(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	d := EnglishDistribution()
+	for _, notation := range []string{"8G Q(U)IXOTIc", "H4 (A)Z(O)Te", "1A (RE)TAIN(S)", "-QU?", "-"} {
+		move, err := ParseMove(notation, d)
+		if err != nil {
+			t.Fatalf("ParseMove(%q) error = %v", notation, err)
+		}
+		if got := move.Notation(d.Alphabet()); got != notation {
+			t.Errorf("Notation() = %q, want %q", got, notation)
+		}
+	}
+}
+
+func TestDottedNotation(t *testing.T) {
+	d := EnglishDistribution()
+	for notation, want := range map[string]string{
+		"8G Q(U)IXOTIc":  "8G Q.IXOTIc",
+		"1A (RE)TAIN(S)": "1A ..TAIN.",
+		"-QU?":           "-QU?",
+	} {
+		move, err := ParseMove(notation, d)
+		if err != nil {
+			t.Fatalf("ParseMove(%q) error = %v", notation, err)
+		}
+		if got := move.DottedNotation(d.Alphabet()); got != want {
+			t.Errorf("DottedNotation() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestParseRack(t *testing.T) {
+	d, err := ParseDistribution("spanish", strings.NewReader("A 12 1\nC 4 3\nCH 1 5\nH 2 4\nO 9 1\n? 2 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rack, err := ParseRack("cha?[C][H]", d)
+	if err != nil {
+		t.Fatalf("ParseRack() error = %v", err)
+	}
+	var symbols []string
+	for _, tile := range rack {
+		symbols = append(symbols, d.Alphabet().Display(tile.Letter))
+	}
+	if got := strings.Join(symbols, " "); got != "CH A ? C H" || !rack[2].IsBlank || rack[0].IsBlank {
+		t.Errorf("ParseRack() = %s, want CH A ? C H with only the ? blank", got)
+	}
+	if _, err := ParseRack("CHZ", d); err == nil {
+		t.Error("ParseRack(CHZ) succeeded, want an unknown tile error")
+	}
+}
+
+func TestNotationDigraphs(t *testing.T) {
+	d, err := ParseDistribution("spanish", strings.NewReader("A 12 1\nC 4 3\nCH 1 5\nH 2 4\nO 9 1\nS 6 1\n? 2 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	move, err := ParseMove("8H chOC[C][H]", d)
+	if err != nil {
+		t.Fatalf("ParseMove() error = %v", err)
+	}
+	if len(move.TilesPlaced) != 5 || !move.TilesPlaced[0].Tile.IsBlank || move.TilesPlaced[1].Tile.IsBlank {
+		t.Fatalf("placed %+v, want a blank CH then O, C, C and H", move.TilesPlaced)
+	}
+	if got := move.Notation(d.Alphabet()); got != "8H chOCCH" {
+		t.Errorf("Notation() = %q, want 8H chOCCH", got)
+	}
+}
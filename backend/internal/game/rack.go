@@ -0,0 +1,205 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownLetter = errors.New("letter is not in the distribution")
+	ErrNotOnRack     = errors.New("tile is not on the rack")
+)
+
+// Rack counts the tiles a player holds, letter by letter in the order of the
+// distribution's Letters, with blanks counted separately. It is a plain
+// value: assigning a Rack copies it, and changing it never allocates.
+type Rack struct {
+	dist   *LetterDistribution
+	counts [MaxLetters]uint8
+	blanks int
+	size   int
+}
+
+// NewRack creates a rack holding the given tiles. Tiles the distribution
+// has no letter for are left off, and the first of them is reported.
+func NewRack(dist *LetterDistribution, tiles []Tile) (Rack, error) {
+	r := Rack{dist: dist}
+	var firstErr error
+	for _, tile := range tiles {
+		if err := r.Add(tile); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return r, firstErr
+}
+
+// NewRackFromCounts creates a rack holding the tiles counted by letter, with
+// '?' for blanks, such as the unseen tiles of a position. Letters the
+// distribution lacks are left off, and the first of them is reported.
+func NewRackFromCounts(dist *LetterDistribution, counts map[rune]int) (Rack, error) {
+	r := Rack{dist: dist}
+	var firstErr error
+	for letter, n := range counts {
+		for ; n > 0; n-- {
+			if err := r.Add(Tile{Letter: letter}); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				break
+			}
+		}
+	}
+	return r, firstErr
+}
+
+// Distribution returns the letter distribution the rack counts tiles for
+func (r *Rack) Distribution() *LetterDistribution {
+	return r.dist
+}
+
+// Add puts a tile on the rack; any blank counts as a blank, whatever letter
+// it was designated as
+func (r *Rack) Add(tile Tile) error {
+	if tile.IsBlank || tile.Letter == BlankLetter {
+		r.AddBlank()
+		return nil
+	}
+	i, ok := r.dist.Index(tile.Letter)
+	if !ok {
+		return fmt.Errorf("%w: %c", ErrUnknownLetter, tile.Letter)
+	}
+	r.AddAt(i)
+	return nil
+}
+
+// Remove takes a tile off the rack; a blank matches any blank
+func (r *Rack) Remove(tile Tile) error {
+	if tile.IsBlank || tile.Letter == BlankLetter {
+		if r.blanks == 0 {
+			return fmt.Errorf("%w: %c", ErrNotOnRack, BlankLetter)
+		}
+		r.RemoveBlank()
+		return nil
+	}
+	i, ok := r.dist.Index(tile.Letter)
+	if !ok || r.counts[i] == 0 {
+		return fmt.Errorf("%w: %c", ErrNotOnRack, tile.Letter)
+	}
+	r.RemoveAt(i)
+	return nil
+}
+
+// RemoveTiles takes one of each tile off the rack, as Remove does. Tiles that
+// are not on the rack are skipped, and the first of them is reported.
+func (r *Rack) RemoveTiles(tiles []Tile) error {
+	var firstErr error
+	for _, tile := range tiles {
+		if err := r.Remove(tile); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Count returns how many of a letter are on the rack, with '?' for blanks
+func (r *Rack) Count(letter rune) int {
+	if letter == BlankLetter {
+		return r.blanks
+	}
+	i, ok := r.dist.Index(letter)
+	if !ok {
+		return 0
+	}
+	return int(r.counts[i])
+}
+
+// CountAt returns how many of the i-th letter of the distribution are on the rack
+func (r *Rack) CountAt(i int) int {
+	return int(r.counts[i])
+}
+
+// AddAt puts one of the i-th letter of the distribution on the rack
+func (r *Rack) AddAt(i int) {
+	r.counts[i]++
+	r.size++
+}
+
+// RemoveAt takes one of the i-th letter of the distribution off the rack.
+// The caller must know there is one there.
+func (r *Rack) RemoveAt(i int) {
+	r.counts[i]--
+	r.size--
+}
+
+// Blanks returns the number of blanks on the rack
+func (r *Rack) Blanks() int {
+	return r.blanks
+}
+
+// AddBlank puts a blank on the rack
+func (r *Rack) AddBlank() {
+	r.blanks++
+	r.size++
+}
+
+// RemoveBlank takes a blank off the rack. The caller must know there is one there.
+func (r *Rack) RemoveBlank() {
+	r.blanks--
+	r.size--
+}
+
+// Len returns the number of tiles on the rack
+func (r *Rack) Len() int {
+	return r.size
+}
+
+// Value returns the total score of the tiles on the rack
+func (r *Rack) Value() int {
+	value := 0
+	for i, letter := range r.dist.Letters {
+		value += int(r.counts[i]) * r.dist.Value(letter)
+	}
+	return value
+}
+
+// Tiles lists the tiles on the rack in distribution order, blanks last
+func (r *Rack) Tiles() []Tile {
+	tiles := make([]Tile, 0, r.size)
+	for i, letter := range r.dist.Letters {
+		for n := 0; n < int(r.counts[i]); n++ {
+			tiles = append(tiles, r.dist.Tile(letter))
+		}
+	}
+	for n := 0; n < r.blanks; n++ {
+		tiles = append(tiles, r.dist.Tile(BlankLetter))
+	}
+	return tiles
+}
+
+// Counts returns the rack as counts by letter, with '?' for blanks
+func (r *Rack) Counts() map[rune]int {
+	counts := make(map[rune]int)
+	for i, letter := range r.dist.Letters {
+		if r.counts[i] > 0 {
+			counts[letter] = int(r.counts[i])
+		}
+	}
+	if r.blanks > 0 {
+		counts[BlankLetter] = r.blanks
+	}
+	return counts
+}
+
+// String shows the rack as its tiles in distribution order, such as "AEINRST?"
+func (r *Rack) String() string {
+	var sb strings.Builder
+	for _, tile := range r.Tiles() {
+		if tile.IsBlank {
+			sb.WriteRune(BlankLetter)
+		} else {
+			sb.WriteString(r.dist.Alphabet().Display(tile.Letter))
+		}
+	}
+	return sb.String()
+}
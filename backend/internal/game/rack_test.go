@@ -0,0 +1,69 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRack(t *testing.T) {
+	dist := EnglishDistribution()
+	rack, err := NewRack(dist, []Tile{
+		dist.Tile('T'), dist.Tile('A'), dist.Tile(BlankLetter), dist.Tile('Q'), dist.Tile('A'),
+	})
+	if err != nil {
+		t.Fatalf("NewRack() error = %v", err)
+	}
+
+	if rack.Len() != 5 || rack.Count('A') != 2 || rack.Blanks() != 1 {
+		t.Errorf("Len %d, A count %d, blanks %d, want 5, 2 and 1", rack.Len(), rack.Count('A'), rack.Blanks())
+	}
+	if got := rack.String(); got != "AAQT?" {
+		t.Errorf("String() = %q, want AAQT?", got)
+	}
+	if got := rack.Value(); got != 13 {
+		t.Errorf("Value() = %d, want 13", got)
+	}
+
+	// Racks are values, so changing a copy leaves the original alone
+	leave := rack
+	if err := leave.Remove(Tile{Letter: 'E', IsBlank: true}); err != nil {
+		t.Fatalf("Remove(designated blank) error = %v", err)
+	}
+	if err := leave.Remove(dist.Tile('Q')); err != nil {
+		t.Fatalf("Remove(Q) error = %v", err)
+	}
+	if leave.String() != "AAT" || rack.String() != "AAQT?" {
+		t.Errorf("leave %q and rack %q, want AAT and AAQT?", leave.String(), rack.String())
+	}
+
+	if err := leave.Remove(dist.Tile('Q')); !errors.Is(err, ErrNotOnRack) {
+		t.Errorf("Remove(missing Q) error = %v, want ErrNotOnRack", err)
+	}
+	if err := leave.Add(Tile{Letter: 'Ñ'}); !errors.Is(err, ErrUnknownLetter) {
+		t.Errorf("Add(Ñ) error = %v, want ErrUnknownLetter", err)
+	}
+}
+
+func TestRackCounts(t *testing.T) {
+	dist := EnglishDistribution()
+	rack, err := NewRackFromCounts(dist, map[rune]int{'E': 2, 'Q': 1, BlankLetter: 1})
+	if err != nil {
+		t.Fatalf("NewRackFromCounts() error = %v", err)
+	}
+	if got := rack.String(); got != "EEQ?" {
+		t.Errorf("String() = %q, want EEQ?", got)
+	}
+
+	// A played blank comes off as a blank; the missing Z is reported
+	err = rack.RemoveTiles([]Tile{dist.Tile('E'), {Letter: 'S', IsBlank: true}, dist.Tile('Z')})
+	if !errors.Is(err, ErrNotOnRack) {
+		t.Errorf("RemoveTiles() error = %v, want ErrNotOnRack", err)
+	}
+	if counts := rack.Counts(); len(counts) != 2 || counts['E'] != 1 || counts['Q'] != 1 {
+		t.Errorf("Counts() = %v, want one E and one Q", counts)
+	}
+
+	if _, err := NewRackFromCounts(dist, map[rune]int{'Ñ': 1}); !errors.Is(err, ErrUnknownLetter) {
+		t.Errorf("NewRackFromCounts(Ñ) error = %v, want ErrUnknownLetter", err)
+	}
+}
@@ -1,5 +1,7 @@
 package game
 
+import "fmt"
+
 // Tile represents a single tile
 type Tile struct {
 	Letter  rune
@@ -21,14 +23,79 @@ const (
 	Vertical
 )
 
+// MoveKind says what a player does on their turn
+type MoveKind int
+
+const (
+	MovePlace    MoveKind = iota // Place tiles on the board
+	MoveExchange                 // Swap tiles with the bag
+	MovePass                     // Do nothing
+)
+
+// MinBagForExchange is the number of tiles that must be in the bag to exchange
+const MinBagForExchange = 7
+
 // Move represents a complete move
 type Move struct {
+	Kind        MoveKind
 	Word        string
 	Position    Position
 	Direction   Direction
 	Score       int
 	TilesPlaced []PlacedTile
-	Leave       []Tile // Remaining tiles
+	Exchanged   []Tile         // Tiles returned to the bag, for MoveExchange
+	Leave       []Tile         // Remaining tiles
+	Breakdown   ScoreBreakdown // How Score was made up, when requested from the scorer
+}
+
+// TilesUsed returns the tiles a move takes off the rack: those placed, or
+// those exchanged
+func (m Move) TilesUsed() []Tile {
+	if m.Kind == MoveExchange {
+		return m.Exchanged
+	}
+	tiles := make([]Tile, len(m.TilesPlaced))
+	for i, placed := range m.TilesPlaced {
+		tiles[i] = placed.Tile
+	}
+	return tiles
+}
+
+// ScoreBreakdown explains a move's score word by word
+type ScoreBreakdown struct {
+	Words []WordScore // Main word first, then cross words
+	Bingo int         // Bonus for playing all seven tiles
+}
+
+// WordScore is the score of a single word formed by a move
+type WordScore struct {
+	Word           string
+	Premiums       []Premium // Premium squares covered by newly placed tiles
+	WordMultiplier int
+	Score          int // Subtotal after all multipliers
+}
+
+// Premium is a premium square used by a move
+type Premium struct {
+	Position   Position
+	Multiplier int
+	IsWord     bool // Word multiplier rather than letter multiplier
+}
+
+// Label returns the short name of the premium, such as "DL" or "TW"
+func (p Premium) Label() string {
+	kind := "L"
+	if p.IsWord {
+		kind = "W"
+	}
+	switch p.Multiplier {
+	case 2:
+		return "D" + kind
+	case 3:
+		return "T" + kind
+	default:
+		return fmt.Sprintf("%d%s", p.Multiplier, kind)
+	}
 }
 
 // PlacedTile is a tile placed at a position
@@ -0,0 +1,507 @@
+// Package gcg reads and writes game records in the .gcg format used by
+// Quackle and other word game programs.
+//
+// A record is a list of pragma lines starting with '#' and event lines
+// starting with '>':
+//
+//	#player1 alice Alice Example
+//	#player2 bob Bob Example
+//	#lexicon NWL2023
+//	>alice: AEINRST 8D RETAINS +72 72
+//	>bob: ?DEIOST H8 .DIOTs +24 24
+//
+// A coordinate starting with the row number, such as 8D, is a play across;
+// one starting with the column letter, such as H8, is a play down. In words
+// '.' marks a tile already on the board and a lower-case letter a blank.
+package gcg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/game"
+	"unicode/utf8"
+)
+
+// EventKind says what an event line records
+type EventKind int
+
+const (
+	EventPlay           EventKind = iota // A placement, exchange or pass, in Move
+	EventWithdrawn                       // The player's last play was challenged off: "--"
+	EventChallengeBonus                  // Points for a play challenged without success: "(challenge)"
+	EventEndRack                         // Points for the opponent's tiles when going out: "(AEI)"
+	EventRackPenalty                     // Points lost for the player's own tiles: "AEI (AEI)"
+	EventTimePenalty                     // Points lost for going over time: "(time)"
+)
+
+// Player is a player named in a #player1 or #player2 pragma
+type Player struct {
+	Nickname string // Used on event lines; no spaces
+	Name     string
+}
+
+// Event is one event line
+type Event struct {
+	Kind   EventKind
+	Player int       // 0 for #player1, 1 for #player2
+	Rack   string    // The player's rack as written, with '?' for blanks; may be empty
+	Move   game.Move // For EventPlay
+	Tiles  string    // The rack scored, for EventEndRack and EventRackPenalty
+	Points int       // Points the event adds, negative for a loss
+	Total  int       // The player's score after the event
+	Notes  []string  // #note lines following the event
+}
+
+// Game is a parsed game record
+type Game struct {
+	Players     [2]Player
+	Encoding    string // From #character-encoding
+	Title       string
+	Description string
+	ID          string // From #id, such as "io 12345"
+	Lexicon     string
+	Extra       []string // Other pragma lines before the events, kept as written
+	Events      []Event
+	FinalRacks  [2]string // From #rack1 and #rack2
+
+	layout *board.Layout
+	dist   *game.LetterDistribution
+	board  *board.Board
+	played []int // Indexes of the plays on the board, so a withdrawn one can be taken back
+}
+
+// New creates an empty record of a game on a standard board with English tiles
+func New(players [2]Player) *Game {
+	return NewWithSetup(players, board.StandardLayout(), game.EnglishDistribution())
+}
+
+// NewWithSetup creates an empty record of a game on the given board layout
+// with the given letter distribution
+func NewWithSetup(players [2]Player, layout *board.Layout, dist *game.LetterDistribution) *Game {
+	return &Game{
+		Players: players,
+		layout:  layout,
+		dist:    dist,
+		board:   board.NewWithLayout(layout),
+	}
+}
+
+// Load reads a .gcg file for a standard board with English tiles
+func Load(filename string) (*Game, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gcg file: %w", err)
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+// LoadWithSetup reads a .gcg file played on the given layout with the given
+// letter distribution
+func LoadWithSetup(filename string, layout *board.Layout, dist *game.LetterDistribution) (*Game, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gcg file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseWithSetup(file, layout, dist)
+}
+
+// Parse reads a game record for a standard board with English tiles
+func Parse(r io.Reader) (*Game, error) {
+	return ParseWithSetup(r, board.StandardLayout(), game.EnglishDistribution())
+}
+
+// ParseWithSetup reads a game record played on the given layout with the
+// given letter distribution, replaying its plays on a board
+func ParseWithSetup(r io.Reader, layout *board.Layout, dist *game.LetterDistribution) (*Game, error) {
+	g := NewWithSetup([2]Player{}, layout, dist)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		var err error
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case strings.HasPrefix(line, "#"):
+			err = g.parsePragma(line)
+		case strings.HasPrefix(line, ">"):
+			err = g.parseEvent(line)
+		default:
+			err = fmt.Errorf("unexpected line")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gcg line %d: %w", lineNum, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading gcg: %w", err)
+	}
+
+	return g, nil
+}
+
+// parsePragma handles a line starting with '#'
+func (g *Game) parsePragma(line string) error {
+	name, value, _ := strings.Cut(line[1:], " ")
+	value = strings.TrimSpace(value)
+
+	switch name {
+	case "player1", "player2":
+		nickname, fullName, _ := strings.Cut(value, " ")
+		if nickname == "" {
+			return fmt.Errorf("#%s needs a nickname", name)
+		}
+		g.Players[name[len(name)-1]-'1'] = Player{Nickname: nickname, Name: strings.TrimSpace(fullName)}
+	case "character-encoding":
+		g.Encoding = value
+	case "title":
+		g.Title = value
+	case "description":
+		g.Description = value
+	case "id":
+		g.ID = value
+	case "lexicon":
+		g.Lexicon = value
+	case "rack1", "rack2":
+		g.FinalRacks[name[len(name)-1]-'1'] = value
+	case "note":
+		if len(g.Events) == 0 {
+			g.Extra = append(g.Extra, line)
+		} else {
+			last := &g.Events[len(g.Events)-1]
+			last.Notes = append(last.Notes, value)
+		}
+	default:
+		if len(g.Events) > 0 {
+			return fmt.Errorf("unknown pragma #%s after the first event", name)
+		}
+		g.Extra = append(g.Extra, line)
+	}
+	return nil
+}
+
+// parseEvent handles a line starting with '>'
+func (g *Game) parseEvent(line string) error {
+	nickname, rest, ok := strings.Cut(line[1:], ":")
+	if !ok {
+		return fmt.Errorf("event has no player")
+	}
+
+	e := Event{Player: -1}
+	for i, p := range g.Players {
+		if p.Nickname == nickname {
+			e.Player = i
+		}
+	}
+	if e.Player < 0 {
+		return fmt.Errorf("unknown player %q", nickname)
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) < 3 {
+		return fmt.Errorf("event needs a play, points and a total")
+	}
+	n := len(fields)
+	points, err := strconv.Atoi(fields[n-2])
+	if err != nil {
+		return fmt.Errorf("bad points %q", fields[n-2])
+	}
+	total, err := strconv.Atoi(fields[n-1])
+	if err != nil {
+		return fmt.Errorf("bad total %q", fields[n-1])
+	}
+	e.Points, e.Total = points, total
+	fields = fields[:n-2]
+
+	last := fields[len(fields)-1]
+	switch {
+	case len(fields) >= 2 && isCoordinate(fields[len(fields)-2]):
+		e.Kind = EventPlay
+		e.Rack, err = optionalRack(fields[:len(fields)-2])
+		if err == nil {
+			e.Move, err = g.parsePlacement(fields[len(fields)-2], last)
+		}
+	case last == "--":
+		e.Kind = EventWithdrawn
+		e.Rack, err = optionalRack(fields[:len(fields)-1])
+	case last == "(challenge)":
+		e.Kind = EventChallengeBonus
+		e.Rack, err = optionalRack(fields[:len(fields)-1])
+	case last == "(time)":
+		e.Kind = EventTimePenalty
+		e.Rack, err = optionalRack(fields[:len(fields)-1])
+	case strings.HasPrefix(last, "(") && strings.HasSuffix(last, ")"):
+		e.Kind = EventEndRack
+		if points < 0 {
+			e.Kind = EventRackPenalty
+		}
+		e.Tiles = last[1 : len(last)-1]
+		e.Rack, err = optionalRack(fields[:len(fields)-1])
+	case strings.HasPrefix(last, "-"):
+		e.Kind = EventPlay
+		e.Rack, err = optionalRack(fields[:len(fields)-1])
+		if err == nil {
+			e.Move, err = game.ParseMove(last, g.dist)
+		}
+	default:
+		if len(fields) < 2 {
+			return fmt.Errorf("play needs a coordinate and a word")
+		}
+		_, _, err = game.ParseCoordinate(fields[len(fields)-2])
+	}
+	if err != nil {
+		return err
+	}
+	if e.Kind == EventPlay {
+		e.Move.Score = points
+	}
+
+	return g.add(e)
+}
+
+// isCoordinate reports whether a field is a coordinate such as 8H or H8
+func isCoordinate(field string) bool {
+	_, _, err := game.ParseCoordinate(field)
+	return err == nil
+}
+
+// optionalRack returns the rack field, if there is one
+func optionalRack(fields []string) (string, error) {
+	switch len(fields) {
+	case 0:
+		return "", nil
+	case 1:
+		return fields[0], nil
+	default:
+		return "", fmt.Errorf("unexpected %q", strings.Join(fields, " "))
+	}
+}
+
+// add records an event, keeping the board up to date
+func (g *Game) add(e Event) error {
+	switch e.Kind {
+	case EventPlay:
+		if e.Move.Kind == game.MovePlace {
+			g.board.Place(e.Move)
+			g.played = append(g.played, len(g.Events))
+		}
+	case EventWithdrawn:
+		// Take back the player's last play if it is still on the board
+		if n := len(g.played); n > 0 && g.Events[g.played[n-1]].Player == e.Player {
+			for _, placed := range g.Events[g.played[n-1]].Move.TilesPlaced {
+				g.board.SetTile(placed.Position.Row, placed.Position.Col, nil)
+			}
+			g.played = g.played[:n-1]
+		} else {
+			return fmt.Errorf("nothing of %s's to withdraw", g.Players[e.Player].Nickname)
+		}
+	}
+
+	g.Events = append(g.Events, e)
+	return nil
+}
+
+// parsePlacement reads a coordinate and word into a move. Each '.' stands
+// for the tile already on its square and is written out in parentheses, so
+// the word can be read as tournament notation.
+func (g *Game) parsePlacement(coord, word string) (game.Move, error) {
+	pos, dir, err := game.ParseCoordinate(coord)
+	if err != nil {
+		return game.Move{}, err
+	}
+
+	alphabet := g.dist.Alphabet()
+	var notation strings.Builder
+	for rest := word; rest != ""; {
+		if rest[0] == '.' {
+			if !g.board.InBounds(pos.Row, pos.Col) {
+				return game.Move{}, fmt.Errorf("%s %s runs off the board", coord, word)
+			}
+			existing := g.board.GetTile(pos.Row, pos.Col)
+			if existing == nil {
+				return game.Move{}, fmt.Errorf("%s %s plays through an empty square", coord, word)
+			}
+			notation.WriteString("(" + alphabet.Display(existing.Letter) + ")")
+			pos = step(pos, dir, 1)
+			rest = rest[1:]
+			continue
+		}
+
+		n := strings.IndexByte(rest, '.')
+		if n < 0 {
+			n = len(rest)
+		}
+		codes, err := alphabet.Encode(strings.NewReplacer("(", "", ")", "").Replace(rest[:n]))
+		if err != nil {
+			return game.Move{}, fmt.Errorf("%s %s: %w", coord, word, err)
+		}
+		notation.WriteString(rest[:n])
+		pos = step(pos, dir, utf8.RuneCountInString(codes))
+		rest = rest[n:]
+	}
+
+	move, err := game.ParseMove(coord+" "+notation.String(), g.dist)
+	if err != nil {
+		return game.Move{}, err
+	}
+
+	// Placed tiles need empty squares, and letters played through must be there
+	placed := make(map[game.Position]bool, len(move.TilesPlaced))
+	for _, p := range move.TilesPlaced {
+		placed[p.Position] = true
+	}
+	pos = move.Position
+	for _, letter := range move.Word {
+		if !g.board.InBounds(pos.Row, pos.Col) {
+			return game.Move{}, fmt.Errorf("%s %s runs off the board", coord, word)
+		}
+		existing := g.board.GetTile(pos.Row, pos.Col)
+		switch {
+		case placed[pos] && existing != nil:
+			return game.Move{}, fmt.Errorf("%s %s covers a tile already on the board", coord, word)
+		case placed[pos]:
+		case existing == nil:
+			return game.Move{}, fmt.Errorf("%s %s plays through an empty square", coord, word)
+		case existing.Letter != letter:
+			return game.Move{}, fmt.Errorf("%s %s plays through %s, not %s",
+				coord, word, alphabet.Display(existing.Letter), alphabet.Display(letter))
+		}
+		pos = step(pos, dir, 1)
+	}
+	return move, nil
+}
+
+// step moves a position n squares in a direction
+func step(pos game.Position, dir game.Direction, n int) game.Position {
+	if dir == game.Horizontal {
+		pos.Col += n
+	} else {
+		pos.Row += n
+	}
+	return pos
+}
+
+// Board returns the board after every event, without plays that were withdrawn
+func (g *Game) Board() *board.Board {
+	return g.board.Clone()
+}
+
+// Moves returns the plays, exchanges and passes in order, leaving out plays
+// that were withdrawn
+func (g *Game) Moves() []game.Move {
+	withdrawn := make(map[int]bool)
+	var plays []int
+	for i, e := range g.Events {
+		switch {
+		case e.Kind == EventPlay && e.Move.Kind == game.MovePlace:
+			plays = append(plays, i)
+		case e.Kind == EventWithdrawn:
+			withdrawn[plays[len(plays)-1]] = true
+			plays = plays[:len(plays)-1]
+		}
+	}
+
+	var moves []game.Move
+	for i, e := range g.Events {
+		if e.Kind == EventPlay && !withdrawn[i] {
+			moves = append(moves, e.Move)
+		}
+	}
+	return moves
+}
+
+// AddMove records a player's move from rack, adding its score to their total
+func (g *Game) AddMove(player int, rack []game.Tile, move game.Move) error {
+	points := move.Score
+	if move.Kind != game.MovePlace {
+		points = 0
+	}
+	return g.add(Event{
+		Kind:   EventPlay,
+		Player: player,
+		Rack:   RackString(rack, g.dist.Alphabet()),
+		Move:   move,
+		Points: points,
+		Total:  g.Score(player) + points,
+	})
+}
+
+// AddEndRack records the points a player gets for the opponent's tiles
+// when going out
+func (g *Game) AddEndRack(player int, opponentRack []game.Tile, points int) {
+	g.Events = append(g.Events, Event{
+		Kind:   EventEndRack,
+		Player: player,
+		Tiles:  RackString(opponentRack, g.dist.Alphabet()),
+		Points: points,
+		Total:  g.Score(player) + points,
+	})
+}
+
+// ToMove returns the player whose turn follows the last play, exchange or
+// pass. A withdrawn play loses its player the turn too. End racks,
+// penalties and challenge bonuses come between turns and leave it alone, so
+// once a player has gone out it is the other player's.
+func (g *Game) ToMove() int {
+	toMove := 0
+	for _, e := range g.Events {
+		if e.Kind == EventPlay || e.Kind == EventWithdrawn {
+			toMove = 1 - e.Player
+		}
+	}
+	return toMove
+}
+
+// Rack returns the tiles a player holds after the last event, as written:
+// their #rack pragma, or else the tiles the opponent scored for going out
+func (g *Game) Rack(player int) string {
+	if g.FinalRacks[player] != "" {
+		return g.FinalRacks[player]
+	}
+	for i := len(g.Events) - 1; i >= 0; i-- {
+		if e := g.Events[i]; e.Kind == EventEndRack && e.Player != player {
+			return e.Tiles
+		}
+	}
+	return ""
+}
+
+// Score returns a player's total after the last event
+func (g *Game) Score(player int) int {
+	for i := len(g.Events) - 1; i >= 0; i-- {
+		if g.Events[i].Player == player {
+			return g.Events[i].Total
+		}
+	}
+	return 0
+}
+
+// RackString writes tiles as a rack, spelled as the alphabet displays them
+// with '?' for blanks
+func RackString(tiles []game.Tile, alphabet *game.Alphabet) string {
+	var sb strings.Builder
+	for _, tile := range tiles {
+		if tile.IsBlank {
+			sb.WriteRune(game.BlankLetter)
+		} else {
+			sb.WriteString(alphabet.Display(tile.Letter))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,262 @@
+package gcg
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/game"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, name := range []string{"testdata/club.gcg", "testdata/penalties.gcg"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, err := Parse(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		var buf bytes.Buffer
+		n, err := g.WriteTo(&buf)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if n != int64(buf.Len()) {
+			t.Errorf("%s: WriteTo reported %d bytes, wrote %d", name, n, buf.Len())
+		}
+		if buf.String() != string(data) {
+			t.Errorf("%s: round trip changed the record:\n%s", name, buf.String())
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	g, err := Load("testdata/club.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Players[1] != (Player{Nickname: "bob", Name: "Bob Example"}) || g.Lexicon != "NWL2023" {
+		t.Errorf("headers parsed as %+v, lexicon %q", g.Players, g.Lexicon)
+	}
+	if got := g.Events[0].Notes; len(got) != 1 || got[0] != "Opening bingo" {
+		t.Errorf("notes on the first event = %q", got)
+	}
+
+	// The withdrawn phony leaves six moves: four placements, an exchange and a pass
+	moves := g.Moves()
+	var kinds []game.MoveKind
+	for _, m := range moves {
+		kinds = append(kinds, m.Kind)
+	}
+	wantKinds := []game.MoveKind{game.MovePlace, game.MovePlace, game.MoveExchange, game.MovePlace, game.MovePass, game.MovePlace}
+	if len(kinds) != len(wantKinds) {
+		t.Fatalf("got move kinds %v, want %v", kinds, wantKinds)
+	}
+	for i := range kinds {
+		if kinds[i] != wantKinds[i] {
+			t.Fatalf("got move kinds %v, want %v", kinds, wantKinds)
+		}
+	}
+
+	idiots := moves[1]
+	if idiots.Word != "IDIOTS" || idiots.Direction != game.Vertical || idiots.Position != (game.Position{Row: 7, Col: 7}) {
+		t.Errorf("IDIOTs parsed as %s at %v going %v", idiots.Word, idiots.Position, idiots.Direction)
+	}
+	if len(idiots.TilesPlaced) != 5 || !idiots.TilesPlaced[4].Tile.IsBlank || idiots.TilesPlaced[4].Tile.Value != 0 {
+		t.Errorf("IDIOTs should place five tiles ending in a blank, got %+v", idiots.TilesPlaced)
+	}
+	if got := RackString(moves[2].Exchanged, game.EnglishAlphabet()); got != "QVVWW" {
+		t.Errorf("exchanged %q, want QVVWW", got)
+	}
+	if moves[3].Word != "HOMES" || moves[3].Score != 22 {
+		t.Errorf("HOME. parsed as %s for %d", moves[3].Word, moves[3].Score)
+	}
+
+	b := g.Board()
+	for _, tc := range []struct {
+		row, col int
+		want     rune
+	}{
+		{7, 3, 'R'}, {12, 7, 'S'}, {12, 3, 'H'}, {11, 3, 'U'}, {12, 8, 0},
+	} {
+		tile := b.GetTile(tc.row, tc.col)
+		switch {
+		case tc.want == 0 && tile != nil:
+			t.Errorf("(%d,%d) holds %c from the withdrawn play", tc.row, tc.col, tile.Letter)
+		case tc.want != 0 && (tile == nil || tile.Letter != tc.want):
+			t.Errorf("(%d,%d) = %v, want %c", tc.row, tc.col, tile, tc.want)
+		}
+	}
+
+	if g.Score(0) != 112 || g.Score(1) != 24 {
+		t.Errorf("final scores %d-%d, want 112-24", g.Score(0), g.Score(1))
+	}
+}
+
+func TestParsePenalties(t *testing.T) {
+	g, err := Load("testdata/penalties.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	za := g.Events[0].Move
+	if za.Direction != game.Vertical || za.Position != (game.Position{Row: 6, Col: 7}) {
+		t.Errorf("H7 ZA parsed at %v going %v", za.Position, za.Direction)
+	}
+
+	var kinds []EventKind
+	for _, e := range g.Events[4:] {
+		kinds = append(kinds, e.Kind)
+	}
+	if len(kinds) != 3 || kinds[0] != EventRackPenalty || kinds[1] != EventRackPenalty || kinds[2] != EventTimePenalty {
+		t.Errorf("penalty events parsed as %v", kinds)
+	}
+	if g.Events[4].Tiles != "EIJQU" || g.FinalRacks[1] != "BCDFGHK" {
+		t.Errorf("penalty tiles %q and final rack %q", g.Events[4].Tiles, g.FinalRacks[1])
+	}
+	if len(g.Extra) != 1 || g.Extra[0] != "#tile-set english" {
+		t.Errorf("extra pragmas = %q", g.Extra)
+	}
+}
+
+func TestToMove(t *testing.T) {
+	g, err := Load("testdata/club.gcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.ToMove() != 1 || g.Rack(1) != "AEELNRU" {
+		t.Errorf("after alice goes out, player %d is to move with %q, want bob with AEELNRU", g.ToMove(), g.Rack(1))
+	}
+
+	// Penalties after the last turn do not pass it on
+	record := "#player1 a A\n#player2 b B\n>a: ABC 8H CAB +14 14\n>b: DOG - +0 0\n>a: (time) -10 4\n"
+	if g, err = Parse(strings.NewReader(record)); err != nil {
+		t.Fatal(err)
+	}
+	if g.ToMove() != 0 {
+		t.Errorf("after b passes and a is penalised, player %d is to move, want a", g.ToMove())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	header := "#player1 a A\n#player2 b B\n"
+	for _, tc := range []struct {
+		name, record, want string
+	}{
+		{"unknown player", ">c: ABC 8H CAB +14 14\n", "unknown player"},
+		{"bad coordinate", ">a: ABC 8 CAB +14 14\n", "bad coordinate"},
+		{"covered square", ">a: ABC 8H CAB +14 14\n>b: DOG 8H DOG +10 10\n", "already on the board"},
+		{"empty through square", ">a: ABC 8H .AB +14 14\n", "empty square"},
+		{"wrong through letter", ">a: ABC 8H CAB +14 14\n>b: DOG H8 (D)OG +5 5\n", "plays through C"},
+		{"nothing to withdraw", ">a: ABC -- +0 0\n", "nothing"},
+		{"bad points", ">a: ABC 8H CAB x 14\n", "bad points"},
+		{"stray text", "hello\n", "line 3"},
+	} {
+		_, err := Parse(strings.NewReader(header + tc.record))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got error %v, want one mentioning %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestParseParentheses(t *testing.T) {
+	record := "#player1 a A\n#player2 b B\n>a: BAT H7 BAT +10 10\n>b: BAT J7 BAT +10 10\n>a: N 8H (A)N(A) +3 13\n"
+	g, err := Parse(strings.NewReader(record))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ana := g.Moves()[2]
+	if ana.Word != "ANA" || len(ana.TilesPlaced) != 1 || ana.TilesPlaced[0].Position != (game.Position{Row: 7, Col: 8}) {
+		t.Errorf("(A)N(A) parsed as %s placing %+v", ana.Word, ana.TilesPlaced)
+	}
+}
+
+func TestDigraphRoundTrip(t *testing.T) {
+	dist, err := game.ParseDistribution("spanish", strings.NewReader("A 12 1\nC 4 3\nCH 1 5\nE 12 1\nL 4 1\nLL 1 8\nO 9 1\nR 5 1\nRR 1 8\n? 2 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alphabet := dist.Alphabet()
+
+	record := "#player1 a A\n#player2 b B\n" +
+		">a: CORRALE 8H CORRAL +14 14\n" +
+		">b: ?ACHEOL H8 .ACHe +10 10\n" +
+		">a: ELLO -LL +0 14\n"
+	g, err := ParseWithSetup(strings.NewReader(record), board.StandardLayout(), dist)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moves := g.Moves()
+	if got := alphabet.Decode(moves[0].Word); got != "CORRAL" || len(moves[0].TilesPlaced) != 5 {
+		t.Errorf("CORRAL parsed as %s placing %d tiles", got, len(moves[0].TilesPlaced))
+	}
+	cache := moves[1]
+	if got := alphabet.Decode(cache.Word); got != "CACHE" || len(cache.TilesPlaced) != 3 {
+		t.Errorf(".ACHe parsed as %s placing %d tiles", got, len(cache.TilesPlaced))
+	}
+	if ch := g.Board().GetTile(9, 7); ch == nil || alphabet.Display(ch.Letter) != "CH" {
+		t.Errorf("H10 = %+v, want CH", ch)
+	}
+	if got := RackString(moves[2].Exchanged, alphabet); got != "LL" || len(moves[2].Exchanged) != 1 {
+		t.Errorf("exchanged %q in %d tiles, want one LL", got, len(moves[2].Exchanged))
+	}
+
+	var buf bytes.Buffer
+	if _, err := g.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != record {
+		t.Errorf("round trip changed the record:\n%s", buf.String())
+	}
+}
+
+func TestRecordGame(t *testing.T) {
+	dist := game.EnglishDistribution()
+	tiles := func(letters string) []game.Tile {
+		var out []game.Tile
+		for _, letter := range letters {
+			out = append(out, dist.Tile(letter))
+		}
+		return out
+	}
+
+	g := New([2]Player{{Nickname: "me"}, {Nickname: "you"}})
+	cat := game.Move{
+		Kind:      game.MovePlace,
+		Word:      "CAT",
+		Position:  game.Position{Row: 7, Col: 7},
+		Direction: game.Horizontal,
+		Score:     10,
+		TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 7, Col: 7}, Tile: dist.Tile('C')},
+			{Position: game.Position{Row: 7, Col: 8}, Tile: game.Tile{Letter: 'A', IsBlank: true}},
+			{Position: game.Position{Row: 7, Col: 9}, Tile: dist.Tile('T')},
+		},
+	}
+	if err := g.AddMove(0, tiles("CT?DEFG"), cat); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddMove(1, tiles("QUIZ"), game.Move{Kind: game.MoveExchange, Exchanged: tiles("QZ")}); err != nil {
+		t.Fatal(err)
+	}
+	g.AddEndRack(0, tiles("IU"), 4)
+
+	var buf bytes.Buffer
+	if _, err := g.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "#player1 me\n#player2 you\n>me: CT?DEFG 8H CaT +10 10\n>you: QUIZ -QZ +0 0\n>me: (IU) +4 14\n"
+	if buf.String() != want {
+		t.Errorf("wrote\n%s\nwant\n%s", buf.String(), want)
+	}
+	if tile := g.Board().GetTile(7, 8); tile == nil || !tile.IsBlank {
+		t.Error("recorded play is not on the board")
+	}
+}
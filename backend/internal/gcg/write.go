@@ -0,0 +1,87 @@
+package gcg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteTo writes the record in .gcg format
+func (g *Game) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	pragma := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(cw, "#%s %s\n", name, value)
+		}
+	}
+	pragma("character-encoding", g.Encoding)
+	for i, p := range g.Players {
+		pragma(fmt.Sprintf("player%d", i+1), strings.TrimSpace(p.Nickname+" "+p.Name))
+	}
+	pragma("title", g.Title)
+	pragma("description", g.Description)
+	pragma("id", g.ID)
+	pragma("lexicon", g.Lexicon)
+	for _, line := range g.Extra {
+		fmt.Fprintln(cw, line)
+	}
+
+	for _, e := range g.Events {
+		fmt.Fprintf(cw, ">%s: %s %+d %d\n", g.Players[e.Player].Nickname, g.eventText(e), e.Points, e.Total)
+		for _, note := range e.Notes {
+			pragma("note", note)
+		}
+	}
+
+	pragma("rack1", g.FinalRacks[0])
+	pragma("rack2", g.FinalRacks[1])
+
+	if err := cw.w.(*bufio.Writer).Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	if cw.err != nil {
+		return cw.n, fmt.Errorf("failed to write gcg: %w", cw.err)
+	}
+	return cw.n, nil
+}
+
+// eventText writes what comes between the player and the points
+func (g *Game) eventText(e Event) string {
+	var fields []string
+	if e.Rack != "" {
+		fields = append(fields, e.Rack)
+	}
+
+	switch e.Kind {
+	case EventPlay:
+		fields = append(fields, e.Move.DottedNotation(g.dist.Alphabet()))
+	case EventWithdrawn:
+		fields = append(fields, "--")
+	case EventChallengeBonus:
+		fields = append(fields, "(challenge)")
+	case EventEndRack, EventRackPenalty:
+		fields = append(fields, "("+e.Tiles+")")
+	case EventTimePenalty:
+		fields = append(fields, "(time)")
+	}
+	return strings.Join(fields, " ")
+}
+
+// countingWriter counts bytes written and keeps the first error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
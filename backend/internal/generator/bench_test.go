@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+)
+
+var (
+	benchLexiconMu sync.Mutex
+	benchLexicons  = make(map[string]*gaddag.Compact)
+)
+
+// loadBenchLexicon loads a dictionary once for all benchmarks in the package
+func loadBenchLexicon(b *testing.B, name string) *gaddag.Compact {
+	b.Helper()
+
+	benchLexiconMu.Lock()
+	defer benchLexiconMu.Unlock()
+
+	if lexicon, ok := benchLexicons[name]; ok {
+		return lexicon
+	}
+
+	g, err := gaddag.LoadFromFile("../../dictionaries/" + name + ".txt")
+	if err != nil {
+		b.Skipf("Skipping benchmark: %v", err)
+	}
+	g.Minimize()
+	benchLexicons[name] = g.Compact()
+	return benchLexicons[name]
+}
+
+// benchBoard returns a mid-game board with HOUSE across and OVEN down
+func benchBoard() *board.Board {
+	b := board.New()
+	for i, letter := range "HOUSE" {
+		b.SetTile(7, 5+i, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+	}
+	for i, letter := range "VEN" {
+		b.SetTile(8+i, 6, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+	}
+	return b
+}
+
+func BenchmarkGenerateMovesWithBlanks(b *testing.B) {
+	lexicon := loadBenchLexicon(b, "NWL2023")
+	rack := []game.Tile{
+		{Letter: 'A', Value: 1},
+		{Letter: 'E', Value: 1},
+		{Letter: 'R', Value: 1},
+		{Letter: 'S', Value: 1},
+		{Letter: 'T', Value: 1},
+		{Letter: game.BlankLetter, IsBlank: true},
+		{Letter: game.BlankLetter, IsBlank: true},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(lexicon, benchBoard()).GenerateMoves(rack)
+	}
+}
+
+// midGameBoard returns a board with HOUSE, OVEN, EXTRA, AJAR and NO played
+func midGameBoard() *board.Board {
+	b := board.New()
+	place := func(row, col int, dir game.Direction, word string) {
+		for _, letter := range word {
+			b.SetTile(row, col, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+			if dir == game.Horizontal {
+				col++
+			} else {
+				row++
+			}
+		}
+	}
+	place(7, 5, game.Horizontal, "HOUSE")
+	place(7, 6, game.Vertical, "OVEN")
+	place(7, 9, game.Vertical, "EXTRA")
+	place(11, 9, game.Horizontal, "AJAR")
+	place(10, 6, game.Horizontal, "NO")
+	return b
+}
+
+func BenchmarkGenerateMovesParallel(b *testing.B) {
+	lexicon := loadBenchLexicon(b, "CSW24")
+	rack := []game.Tile{
+		{Letter: 'E', Value: 1},
+		{Letter: 'I', Value: 1},
+		{Letter: 'N', Value: 1},
+		{Letter: 'R', Value: 1},
+		{Letter: 'S', Value: 1},
+		{Letter: 'T', Value: 1},
+		{Letter: game.BlankLetter, IsBlank: true},
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			New(lexicon, midGameBoard()).GenerateMoves(rack)
+		}
+	})
+	for _, workers := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				New(lexicon, midGameBoard()).GenerateMovesParallel(rack, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+)
+
+// crossChecks holds, for every square and each direction of play, the set of
+// letters that form a valid perpendicular word there and the value of the
+// perpendicular tiles already on the board, along with which squares are
+// anchors. Letters are bits indexed by their position in the
+// distribution's alphabet.
+type crossChecks struct {
+	size    int
+	letters []rune
+	checks  [2][]uint64 // [direction][row*size+col]
+	sums    [2][]int    // Value of the tiles in the perpendicular word, without the square itself
+	crossed [2][]bool   // Whether the square has perpendicular neighbours at all
+	anchors []bool
+}
+
+// allLetters has a bit set for every letter of the alphabet
+func (cc *crossChecks) allLetters() uint64 {
+	if len(cc.letters) == 64 {
+		return ^uint64(0)
+	}
+	return 1<<uint(len(cc.letters)) - 1
+}
+
+// computeCrossChecks works out the cross-checks for every square
+func (g *Generator) computeCrossChecks() {
+	size := g.board.Size()
+	cc := &crossChecks{
+		size:    size,
+		letters: g.dist.Letters,
+	}
+	for dir := range cc.checks {
+		cc.checks[dir] = make([]uint64, size*size)
+		cc.sums[dir] = make([]int, size*size)
+		cc.crossed[dir] = make([]bool, size*size)
+	}
+	cc.anchors = make([]bool, size*size)
+	g.cross = cc
+
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			g.updateSquare(row, col)
+		}
+	}
+}
+
+// UpdateCrossChecks refreshes the cross-checks after move's tiles have been
+// placed on the board, or taken back off it. Only the move's squares, their
+// neighbours and the ends of the words running through them can change, so
+// the rest are left alone. Any other change to the board needs a new
+// Generator.
+func (g *Generator) UpdateCrossChecks(move game.Move) {
+	if g.cross == nil {
+		return // Not worked out yet, so nothing is stale
+	}
+
+	// The start square is an anchor only while the board is empty
+	start := g.board.Layout().Start
+	g.updateSquare(start.Row, start.Col)
+
+	for _, placed := range move.TilesPlaced {
+		square := anchorSquare{placed.Position.Row, placed.Position.Col}
+		g.updateSquare(square.row, square.col)
+
+		// The first empty square each way past the square: its neighbour, or
+		// the end of the run of tiles the neighbour is part of
+		for _, dir := range []game.Direction{game.Horizontal, game.Vertical} {
+			for _, sense := range []int{-1, 1} {
+				edge := g.prevPos(square, dir)
+				if sense > 0 {
+					edge = g.nextPos(square, dir)
+				}
+				edge = g.wordEdge(edge, dir, sense)
+				if g.board.InBounds(edge.row, edge.col) {
+					g.updateSquare(edge.row, edge.col)
+				}
+			}
+		}
+	}
+}
+
+// wordEdge returns the first empty square, or off-board square, from pos
+// onwards in the given direction and sense
+func (g *Generator) wordEdge(pos anchorSquare, dir game.Direction, sense int) anchorSquare {
+	step := g.nextPos
+	if sense < 0 {
+		step = g.prevPos
+	}
+	for g.board.GetTile(pos.row, pos.col) != nil {
+		pos = step(pos, dir)
+	}
+	return pos
+}
+
+// updateSquare recomputes both directions' cross-checks for one square
+func (g *Generator) updateSquare(row, col int) {
+	cc := g.cross
+	i := row*cc.size + col
+	cc.anchors[i] = g.board.IsAnchor(row, col)
+
+	for _, dir := range []game.Direction{game.Horizontal, game.Vertical} {
+		if g.board.GetTile(row, col) != nil {
+			cc.checks[dir][i], cc.sums[dir][i], cc.crossed[dir][i] = 0, 0, false
+			continue
+		}
+
+		// A play in dir forms its cross word in the other direction
+		crossDir := game.Vertical
+		if dir == game.Vertical {
+			crossDir = game.Horizontal
+		}
+
+		prefix, suffix, sum := g.crossWordAround(anchorSquare{row, col}, crossDir)
+		if len(prefix) == 0 && len(suffix) == 0 {
+			cc.checks[dir][i], cc.sums[dir][i], cc.crossed[dir][i] = cc.allLetters(), 0, false
+			continue
+		}
+
+		var allowed uint64
+		for bit, letter := range cc.letters {
+			if g.formsWord(prefix, letter, suffix) {
+				allowed |= 1 << uint(bit)
+			}
+		}
+		cc.checks[dir][i], cc.sums[dir][i], cc.crossed[dir][i] = allowed, sum, true
+	}
+}
+
+// crossWordAround returns the tiles before and after an empty square in the
+// given direction, with the prefix listed nearest first, and their value
+func (g *Generator) crossWordAround(pos anchorSquare, dir game.Direction) (prefix, suffix []rune, sum int) {
+	for p := g.prevPos(pos, dir); g.board.GetTile(p.row, p.col) != nil; p = g.prevPos(p, dir) {
+		tile := g.board.GetTile(p.row, p.col)
+		prefix = append(prefix, tile.Letter)
+		sum += g.tileValue(tile)
+	}
+	for p := g.nextPos(pos, dir); g.board.GetTile(p.row, p.col) != nil; p = g.nextPos(p, dir) {
+		tile := g.board.GetTile(p.row, p.col)
+		suffix = append(suffix, tile.Letter)
+		sum += g.tileValue(tile)
+	}
+	return prefix, suffix, sum
+}
+
+// formsWord checks prefix + letter + suffix with a single GADDAG path:
+// the letter, the prefix reversed, the separator, then the suffix
+func (g *Generator) formsWord(reversedPrefix []rune, letter rune, suffix []rune) bool {
+	node, ok := g.gaddag.Edge(g.gaddag.Root(), letter)
+	if !ok {
+		return false
+	}
+	for _, l := range reversedPrefix {
+		if node, ok = g.gaddag.Edge(node, l); !ok {
+			return false
+		}
+	}
+	if node, ok = g.gaddag.Edge(node, gaddag.Separator); !ok {
+		return false
+	}
+	for _, l := range suffix {
+		if node, ok = g.gaddag.Edge(node, l); !ok {
+			return false
+		}
+	}
+	return g.gaddag.IsTerminal(node)
+}
+
+func (g *Generator) tileValue(tile *game.Tile) int {
+	if tile.IsBlank {
+		return 0
+	}
+	return g.dist.Value(tile.Letter)
+}
+
+// allowsLetter reports whether letter can be placed at pos in a play going
+// in dir. The cross-checks must already have been computed.
+func (g *Generator) allowsLetter(pos anchorSquare, letter rune, dir game.Direction) bool {
+	i, ok := g.dist.Index(letter)
+	return ok && g.allows(pos, i, dir)
+}
+
+// allows is allowsLetter for the i-th letter of the distribution
+func (g *Generator) allows(pos anchorSquare, i int, dir game.Direction) bool {
+	return g.cross.checks[dir][pos.row*g.cross.size+pos.col]&(1<<uint(i)) != 0
+}
+
+// isAnchor reports whether a square is an anchor, using the precomputed
+// table rather than scanning the board
+func (g *Generator) isAnchor(row, col int) bool {
+	return g.cross.anchors[row*g.cross.size+col]
+}
+
+// CrossSum returns the value of the tiles a letter placed at (row, col) in a
+// play going in dir would join in its perpendicular word, and whether there
+// is such a word at all
+func (g *Generator) CrossSum(row, col int, dir game.Direction) (int, bool) {
+	if g.cross == nil {
+		g.computeCrossChecks()
+	}
+	i := row*g.cross.size + col
+	return g.cross.sums[dir][i], g.cross.crossed[dir][i]
+}
@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/scorer"
+	"tiletactics/backend/internal/validator"
+)
+
+func newCrossCheckLexicon() *gaddag.GADDAG {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "CATS", "SCAT", "AT", "AS", "TA", "ACT", "ACTS", "TAT", "SAT", "ST"} {
+		g.Add(word)
+	}
+	return g
+}
+
+func boardWithCat() *board.Board {
+	b := board.New()
+	b.SetTile(7, 7, &game.Tile{Letter: 'C', Value: 3})
+	b.SetTile(7, 8, &game.Tile{Letter: 'A', Value: 1})
+	b.SetTile(7, 9, &game.Tile{Letter: 'T', Value: 1})
+	return b
+}
+
+// allowedLetters lists the letters the cross-checks allow at a square
+func allowedLetters(gen *Generator, row, col int, dir game.Direction) string {
+	var letters []rune
+	for _, letter := range gen.dist.Letters {
+		if gen.allowsLetter(anchorSquare{row, col}, letter, dir) {
+			letters = append(letters, letter)
+		}
+	}
+	return string(letters)
+}
+
+func TestCrossChecks(t *testing.T) {
+	gen := New(newCrossCheckLexicon(), boardWithCat())
+	gen.computeCrossChecks()
+
+	tests := []struct {
+		name     string
+		row, col int
+		dir      game.Direction
+		want     string
+		sum      int
+		crossed  bool
+	}{
+		{"Below A, playing across", 8, 8, game.Horizontal, "ST", 1, true},
+		{"Above A, playing across", 6, 8, game.Horizontal, "T", 1, true},
+		{"After CAT, playing down", 7, 10, game.Vertical, "S", 5, true},
+		{"Before CAT, playing down", 7, 6, game.Vertical, "S", 5, true},
+		{"Below A, playing down", 8, 8, game.Vertical, "ABCDEFGHIJKLMNOPQRSTUVWXYZ", 0, false},
+		{"Occupied square", 7, 8, game.Horizontal, "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowedLetters(gen, tt.row, tt.col, tt.dir); got != tt.want {
+				t.Errorf("allowed letters = %q, want %q", got, tt.want)
+			}
+			sum, crossed := gen.CrossSum(tt.row, tt.col, tt.dir)
+			if sum != tt.sum || crossed != tt.crossed {
+				t.Errorf("CrossSum() = %d, %v, want %d, %v", sum, crossed, tt.sum, tt.crossed)
+			}
+		})
+	}
+}
+
+func TestUpdateCrossChecksMatchesRecompute(t *testing.T) {
+	lexicon := newCrossCheckLexicon()
+	b := board.New()
+	gen := New(lexicon, b)
+
+	rack := []game.Tile{{Letter: 'C'}, {Letter: 'A'}, {Letter: 'T'}, {Letter: 'S'}}
+	for turn := 0; turn < 2; turn++ {
+		moves := gen.GenerateMoves(rack)
+		if len(moves) == 0 {
+			t.Fatalf("turn %d: no moves generated", turn)
+		}
+
+		move := moves[0]
+		for _, placed := range move.TilesPlaced {
+			tile := placed.Tile
+			b.SetTile(placed.Position.Row, placed.Position.Col, &tile)
+		}
+		gen.UpdateCrossChecks(move)
+
+		fresh := New(lexicon, b)
+		fresh.computeCrossChecks()
+		if !reflect.DeepEqual(gen.cross, fresh.cross) {
+			t.Fatalf("turn %d: cross-checks after %s differ from a full recompute", turn, move.Word)
+		}
+	}
+}
+
+func TestUpdateCrossChecksAfterTakingBack(t *testing.T) {
+	lexicon := newCrossCheckLexicon()
+	b := boardWithCat()
+	gen := New(lexicon, b)
+
+	rack := []game.Tile{{Letter: 'S'}, {Letter: 'A'}, {Letter: 'T'}}
+	want := New(lexicon, b.Clone())
+	want.computeCrossChecks()
+	for _, move := range gen.GenerateMoves(rack) {
+		b.Place(move)
+		gen.UpdateCrossChecks(move)
+		b.Unplace(move)
+		gen.UpdateCrossChecks(move)
+
+		if !reflect.DeepEqual(gen.cross, want.cross) {
+			t.Fatalf("cross-checks after taking back %s at %v differ from a full recompute", move.Word, move.Position)
+		}
+	}
+}
+
+func TestScoresMatchScorer(t *testing.T) {
+	b := boardWithCat()
+	rack := []game.Tile{{Letter: 'S', Value: 1}, {Letter: 'A', Value: 1}, {Letter: 'T', Value: 1}, {Letter: game.BlankLetter, IsBlank: true}}
+
+	moves := New(newCrossCheckLexicon(), b).GenerateMoves(rack)
+	sc := scorer.NewWithDistribution(b, game.EnglishDistribution())
+	for _, move := range moves {
+		if want := sc.ScoreMove(move); move.Score != want {
+			t.Errorf("%s at %v dir %d scored %d, want %d", move.Word, move.Position, move.Direction, move.Score, want)
+		}
+	}
+}
+
+func TestGeneratedMovesAreLegal(t *testing.T) {
+	lexicon := newCrossCheckLexicon()
+	b := boardWithCat()
+	rack := []game.Tile{{Letter: 'S', Value: 1}, {Letter: 'A', Value: 1}, {Letter: 'T', Value: 1}, {Letter: game.BlankLetter, IsBlank: true}}
+
+	moves := New(lexicon, b).GenerateMoves(rack)
+	if len(moves) == 0 {
+		t.Fatal("no moves generated")
+	}
+
+	v := validator.New(lexicon)
+	for _, move := range moves {
+		if err := v.Validate(b, rack, move); err != nil {
+			t.Errorf("generated %s at %v dir %d is illegal: %v", move.Word, move.Position, move.Direction, err)
+		}
+	}
+}
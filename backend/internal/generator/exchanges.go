@@ -0,0 +1,57 @@
+package generator
+
+import "tiletactics/backend/internal/game"
+
+// GenerateAllMoves lists everything the player could do: placements highest
+// score first, then exchanges if the bag allows them, then passing
+func (g *Generator) GenerateAllMoves(rack []game.Tile, bagLen int) []game.Move {
+	moves := g.GenerateMoves(rack)
+	moves = append(moves, g.GenerateExchanges(rack, bagLen)...)
+	return append(moves, game.Move{Kind: game.MovePass})
+}
+
+// GenerateExchanges lists every distinct set of one or more tiles that could
+// be exchanged from the rack, or nothing when the bag holds fewer than
+// game.MinBagForExchange tiles. Exchanged blanks are undesignated.
+func (g *Generator) GenerateExchanges(rack []game.Tile, bagLen int) []game.Move {
+	if bagLen < game.MinBagForExchange {
+		return nil
+	}
+
+	counter, _ := game.NewRack(g.dist, rack)
+	letters := len(g.dist.Letters)
+
+	var moves []game.Move
+	var chosen []game.Tile
+
+	// choose picks how many of the i-th letter to exchange, with blanks last
+	var choose func(i int)
+	choose = func(i int) {
+		if i > letters {
+			if len(chosen) > 0 {
+				moves = append(moves, game.Move{
+					Kind:      game.MoveExchange,
+					Exchanged: append([]game.Tile(nil), chosen...),
+				})
+			}
+			return
+		}
+
+		available, tile := counter.Blanks(), g.dist.Tile(game.BlankLetter)
+		if i < letters {
+			available, tile = counter.CountAt(i), g.dist.Tile(g.dist.Letters[i])
+		}
+
+		before := len(chosen)
+		for n := 0; n <= available; n++ {
+			if n > 0 {
+				chosen = append(chosen, tile)
+			}
+			choose(i + 1)
+		}
+		chosen = chosen[:before]
+	}
+	choose(0)
+
+	return moves
+}
@@ -1,7 +1,9 @@
 package generator
 
 import (
+	"runtime"
 	"sort"
+	"sync"
 	"tiletactics/backend/internal/board"
 	"tiletactics/backend/internal/gaddag"
 	"tiletactics/backend/internal/game"
@@ -9,348 +11,265 @@ import (
 )
 
 type Generator struct {
-	gaddag *gaddag.GADDAG
+	gaddag *gaddag.Compact
 	board  *board.Board
+	dist   *game.LetterDistribution
+	cross  *crossChecks // Worked out on the first search
 }
 
-func New(g *gaddag.GADDAG, b *board.Board) *Generator {
+func New(g gaddag.Lexicon, b *board.Board) *Generator {
+	return NewWithDistribution(g, b, game.EnglishDistribution())
+}
+
+// NewWithDistribution creates a generator that values tiles and tries blanks
+// using the given letter distribution
+func NewWithDistribution(g gaddag.Lexicon, b *board.Board, dist *game.LetterDistribution) *Generator {
 	return &Generator{
-		gaddag: g,
+		gaddag: g.Compact(),
 		board:  b,
+		dist:   dist,
 	}
 }
 
-// GenerateMoves finds all valid moves for the given rack
+// GenerateMoves finds all valid moves for the given rack, highest score first
 func (g *Generator) GenerateMoves(rack []game.Tile) []game.Move {
 	var moves []game.Move
+	g.GenerateFunc(rack, func(move game.Move) bool {
+		moves = append(moves, move)
+		return true
+	})
 
-	// Find all anchor squares
-	anchors := g.findAnchors()
-
-	// For each anchor, generate moves in both directions
-	for _, anchor := range anchors {
-		// Generate horizontal moves through this anchor
-		horizontalMoves := g.generateMovesFromAnchor(anchor, rack, game.Horizontal)
-		moves = append(moves, horizontalMoves...)
-
-		// Generate vertical moves through this anchor
-		verticalMoves := g.generateMovesFromAnchor(anchor, rack, game.Vertical)
-		moves = append(moves, verticalMoves...)
-	}
-
-	// Remove duplicates and invalid words
-	moves = g.removeDuplicates(moves)
-
-	// Score all moves
-	sc := scorer.New(g.board)
-	for i := range moves {
-		moves[i].Score = sc.ScoreMove(moves[i])
-	}
-
-	// Sort by score (highest first)
-	sort.Slice(moves, func(i, j int) bool {
+	// Sort by score (highest first), keeping generation order for ties
+	sort.SliceStable(moves, func(i, j int) bool {
 		return moves[i].Score > moves[j].Score
 	})
 
 	return moves
 }
 
-type anchorSquare struct {
-	row, col int
+// TopMoves returns the k highest scoring moves for the rack, best first,
+// without holding on to the rest
+func (g *Generator) TopMoves(rack []game.Tile, k int) []game.Move {
+	top := NewTopK(k)
+	g.GenerateFunc(rack, func(move game.Move) bool {
+		top.Add(move, float64(move.Score))
+		return true
+	})
+	return top.Moves()
 }
 
-func (g *Generator) findAnchors() []anchorSquare {
-	var anchors []anchorSquare
-
-	for row := 0; row < game.BoardSize; row++ {
-		for col := 0; col < game.BoardSize; col++ {
-			if g.board.IsAnchor(row, col) {
-				anchors = append(anchors, anchorSquare{row, col})
-			}
-		}
+// GenerateFunc calls fn with each valid move for the rack, already scored,
+// as soon as it is found. Moves arrive in generation order rather than by
+// score, and the same word in the same place is only reported once however
+// the blanks could be assigned. Generation stops when fn returns false.
+func (g *Generator) GenerateFunc(rack []game.Tile, fn func(game.Move) bool) {
+	if g.cross == nil {
+		g.computeCrossChecks()
 	}
 
-	return anchors
-}
+	s := g.newSearch(fn)
 
-func (g *Generator) generateMovesFromAnchor(anchor anchorSquare, rack []game.Tile, dir game.Direction) []game.Move {
-	var moves []game.Move
+	// Letters outside the distribution can never be played, so they are
+	// simply left off
+	counter, _ := game.NewRack(g.dist, rack)
 
-	// Convert rack to a more usable format
-	rackMap := make(map[rune]int)
-	var blanks int
-	for _, tile := range rack {
-		if tile.IsBlank {
-			blanks++
-		} else {
-			rackMap[tile.Letter]++
-		}
+	for _, task := range g.searchTasks() {
+		g.searchAnchor(task, &counter, s)
 	}
+}
 
-	// Special case: if anchor is right after existing tiles, start from those tiles
-	prev := g.prevPos(anchor, dir)
-	if prev.row >= 0 && prev.col >= 0 && g.board.GetTile(prev.row, prev.col) != nil {
-		// Find the start of the existing word
-		wordStart := prev
-		for {
-			p := g.prevPos(wordStart, dir)
-			if p.row < 0 || p.col < 0 || g.board.GetTile(p.row, p.col) == nil {
-				break
-			}
-			wordStart = p
-		}
+// GenerateMovesParallel is GenerateMoves spread over a pool of workers, each
+// searching whole anchors in one direction. The board and GADDAG are only
+// read, so the board must not change until it returns. The moves and their
+// order are exactly those of GenerateMoves. Zero or fewer workers means one
+// per CPU.
+func (g *Generator) GenerateMovesParallel(rack []game.Tile, workers int) []game.Move {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if g.cross == nil {
+		g.computeCrossChecks()
+	}
 
-		// Generate moves starting from the existing word
-		movesFromExisting := g.generateMovesFromPosition(wordStart, anchor, rackMap, blanks, dir)
-		moves = append(moves, movesFromExisting...)
-	} else {
-		// Normal case: find all valid starting positions
-		startPos := g.findWordStart(anchor, dir)
-
-		// Try placing tiles starting from each valid position
-		for pos := startPos; pos.row <= anchor.row && pos.col <= anchor.col; pos = g.nextPos(pos, dir) {
-			movesFromPos := g.generateMovesFromPosition(pos, anchor, rackMap, blanks, dir)
-			moves = append(moves, movesFromPos...)
-
-			// If there's already a tile at this position, we can't start before it
-			if g.board.GetTile(pos.row, pos.col) != nil {
-				break
+	counter, _ := game.NewRack(g.dist, rack)
+	tasks := g.searchTasks()
+
+	// Each task collects its own moves so they can be joined in task order
+	results := make([][]game.Move, len(tasks))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rack := counter // Each worker takes tiles from its own copy
+			for i := range next {
+				var moves []game.Move
+				s := g.newSearch(func(move game.Move) bool {
+					moves = append(moves, move)
+					return true
+				})
+				g.searchAnchor(tasks[i], &rack, s)
+				results[i] = moves
 			}
-		}
+		}()
 	}
+	for i := range tasks {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
 
-	return moves
-}
-
-func (g *Generator) findWordStart(anchor anchorSquare, dir game.Direction) anchorSquare {
-	pos := anchor
-
-	// First, backtrack through any existing tiles
-	for {
-		prev := g.prevPos(pos, dir)
-		if prev.row < 0 || prev.col < 0 {
-			break
-		}
-		if g.board.GetTile(prev.row, prev.col) == nil {
-			break
-		}
-		pos = prev
+	var moves []game.Move
+	for _, taskMoves := range results {
+		moves = append(moves, taskMoves...)
 	}
 
-	// Now backtrack through empty squares where we could place tiles
-	emptyCount := 0
-	for {
-		prev := g.prevPos(pos, dir)
+	// Sort by score (highest first), keeping generation order for ties
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].Score > moves[j].Score
+	})
 
-		// Stop if we hit the edge
-		if prev.row < 0 || prev.col < 0 {
-			break
-		}
+	return moves
+}
 
-		// Stop if we hit an existing tile
-		if g.board.GetTile(prev.row, prev.col) != nil {
-			break
-		}
+// searchTask is one anchor searched in one direction
+type searchTask struct {
+	anchor anchorSquare
+	dir    game.Direction
+}
 
-		// Stop if we've gone back 7 spaces (max rack size)
-		emptyCount++
-		if emptyCount >= 7 {
-			break
+// searchTasks lists every anchor in both directions, in generation order.
+// Each move is found from the first anchor it covers, so no two tasks
+// produce the same move.
+func (g *Generator) searchTasks() []searchTask {
+	var tasks []searchTask
+	for _, anchor := range g.findAnchors() {
+		for _, dir := range []game.Direction{game.Horizontal, game.Vertical} {
+			tasks = append(tasks, searchTask{anchor, dir})
 		}
-
-		pos = prev
 	}
-
-	return pos
+	return tasks
 }
 
-func (g *Generator) generateMovesFromPosition(start, _ anchorSquare, rackMap map[rune]int, blanks int, dir game.Direction) []game.Move {
-	var moves []game.Move
-
-	// Start GADDAG traversal from root
-	g.extendRight(
-		g.gaddag.Root(),
-		start,
-		"",                  // word built so far
-		[]game.PlacedTile{}, // tiles placed so far
-		rackMap,
-		blanks,
-		dir,
-		false, // haven't seen anchor yet
-		&moves,
-	)
-
-	return moves
+// searchAnchor finds the moves for one task
+func (g *Generator) searchAnchor(task searchTask, rack *game.Rack, s *search) {
+	g.extendLeft(g.gaddag.Root(), task.anchor, task.anchor, "", nil, rack, task.dir, s)
 }
 
-func (g *Generator) nextPos(pos anchorSquare, dir game.Direction) anchorSquare {
-	if dir == game.Horizontal {
-		return anchorSquare{pos.row, pos.col + 1}
+func (g *Generator) newSearch(fn func(game.Move) bool) *search {
+	return &search{
+		gen:  g,
+		fn:   fn,
+		seen: make(map[string]bool),
 	}
-	return anchorSquare{pos.row + 1, pos.col}
 }
 
-func (g *Generator) prevPos(pos anchorSquare, dir game.Direction) anchorSquare {
-	if dir == game.Horizontal {
-		return anchorSquare{pos.row, pos.col - 1}
-	}
-	return anchorSquare{pos.row - 1, pos.col}
+// search is the state of one GenerateFunc call shared by the traversal
+type search struct {
+	gen     *Generator
+	fn      func(game.Move) bool
+	seen    map[string]bool // Moves already reported
+	stopped bool
 }
 
-// getPerpendicularWord gets the perpendicular word formed at a position
-func (g *Generator) getPerpendicularWord(row, col int, placedTile *game.Tile, dir game.Direction) string {
-	// Determine perpendicular direction
-	perpDir := game.Vertical
-	if dir == game.Vertical {
-		perpDir = game.Horizontal
+// emit scores a move and hands it to the caller unless it is a duplicate
+func (s *search) emit(move game.Move) {
+	key := moveKey(move)
+	if s.seen[key] {
+		return
 	}
+	s.seen[key] = true
 
-	// Find start of perpendicular word
-	startRow, startCol := row, col
-	if perpDir == game.Horizontal {
-		// Move left to find start
-		for startCol > 0 && g.board.GetTile(startRow, startCol-1) != nil {
-			startCol--
-		}
-	} else {
-		// Move up to find start
-		for startRow > 0 && g.board.GetTile(startRow-1, startCol) != nil {
-			startRow--
-		}
+	move.Score = s.gen.scoreMove(move)
+	if !s.fn(move) {
+		s.stopped = true
 	}
+}
 
-	// Build the perpendicular word
-	word := ""
-	r, c := startRow, startCol
-	for r < game.BoardSize && c < game.BoardSize {
-		var tile *game.Tile
-		if r == row && c == col {
-			// This is where we're placing the new tile
-			tile = placedTile
-		} else {
-			tile = g.board.GetTile(r, c)
-		}
-
-		if tile == nil {
-			break
-		}
-
-		// Add letter to word
-		word += string(tile.Letter)
-
-		// Move to next position
-		if perpDir == game.Horizontal {
-			c++
+// scoreMove scores a generated move as the scorer would, taking each cross
+// word's tiles already on the board from the cross-sums instead of walking
+// out to them. Generated moves are always connected, so that is not checked.
+func (g *Generator) scoreMove(move game.Move) int {
+	main, wordMultiplier, cross := 0, 1, 0
+	pos := anchorSquare{move.Position.Row, move.Position.Col}
+	for range move.Word {
+		if tile := g.board.GetTile(pos.row, pos.col); tile != nil {
+			main += g.tileValue(tile)
 		} else {
-			r++
+			multiplier := g.board.GetMultiplier(pos.row, pos.col)
+			value := g.tileValue(placedAt(move, pos)) * multiplier.LetterMultiplier()
+			main += value
+			wordMultiplier *= multiplier.WordMultiplier()
+
+			i := pos.row*g.cross.size + pos.col
+			if g.cross.crossed[move.Direction][i] {
+				cross += (g.cross.sums[move.Direction][i] + value) * multiplier.WordMultiplier()
+			}
 		}
+		pos = g.nextPos(pos, move.Direction)
 	}
 
-	return word
+	score := main*wordMultiplier + cross
+	if len(move.TilesPlaced) == 7 {
+		score += scorer.BingoBonus
+	}
+	return score
 }
 
-// getAllPerpendicularWords gets all perpendicular words formed by a move
-func (g *Generator) getAllPerpendicularWords(move game.Move) []string {
-	perpWords := make(map[string]bool)
-
-	// For each position along the main word (not just placed tiles)
-	row, col := move.Position.Row, move.Position.Col
-
-	for i := 0; i < len(move.Word); i++ {
-		// Check if there's a tile being placed at this position
-		var tileAtPos *game.Tile
-		isNewTile := false
-
-		// Check if this position has a newly placed tile
-		for _, placed := range move.TilesPlaced {
-			if placed.Position.Row == row && placed.Position.Col == col {
-				tileAtPos = &placed.Tile
-				isNewTile = true
-				break
-			}
-		}
-
-		// If no new tile, check for existing tile
-		if !isNewTile {
-			existingTile := g.board.GetTile(row, col)
-			if existingTile != nil {
-				tileAtPos = existingTile
-			} else {
-				// For validation purposes, create a temporary tile
-				tileAtPos = &game.Tile{Letter: rune(move.Word[i])}
-			}
-		}
-
-		// Get perpendicular word at this position
-		perpWord := g.getPerpendicularWord(row, col, tileAtPos, move.Direction)
-
-		// Only add if it's more than one letter (actual perpendicular word)
-		if len(perpWord) > 1 {
-			perpWords[perpWord] = true
+// placedAt returns the tile a move places on a square
+func placedAt(move game.Move, pos anchorSquare) *game.Tile {
+	for i := range move.TilesPlaced {
+		if p := move.TilesPlaced[i].Position; p.Row == pos.row && p.Col == pos.col {
+			return &move.TilesPlaced[i].Tile
 		}
-
-		// Move to next position
-		if move.Direction == game.Horizontal {
-			col++
-		} else {
-			row++
-		}
-	}
-
-	// Convert map to slice
-	result := make([]string, 0, len(perpWords))
-	for word := range perpWords {
-		result = append(result, word)
 	}
-
-	return result
+	return &game.Tile{}
 }
 
-func (g *Generator) removeDuplicates(moves []game.Move) []game.Move {
-	seen := make(map[string]bool)
-	var unique []game.Move
-
-	for _, move := range moves {
-		// Validate that the main word is in the dictionary
-		if !g.gaddag.Contains(move.Word) {
-			continue
-		}
+type anchorSquare struct {
+	row, col int
+}
 
-		// Get all perpendicular words formed by this move
-		perpWords := g.getAllPerpendicularWords(move)
+func (g *Generator) findAnchors() []anchorSquare {
+	var anchors []anchorSquare
 
-		// Validate each perpendicular word
-		validMove := true
-		for _, perpWord := range perpWords {
-			if !g.gaddag.Contains(perpWord) {
-				validMove = false
-				break
+	for row := 0; row < g.board.Size(); row++ {
+		for col := 0; col < g.board.Size(); col++ {
+			if g.isAnchor(row, col) {
+				anchors = append(anchors, anchorSquare{row, col})
 			}
 		}
+	}
 
-		if !validMove {
-			continue
-		}
+	return anchors
+}
 
-		// Create a unique key for each move
-		key := moveKey(move)
-		if !seen[key] {
-			seen[key] = true
-			unique = append(unique, move)
-		}
+func (g *Generator) nextPos(pos anchorSquare, dir game.Direction) anchorSquare {
+	if dir == game.Horizontal {
+		return anchorSquare{pos.row, pos.col + 1}
 	}
-
-	return unique
+	return anchorSquare{pos.row + 1, pos.col}
 }
 
-func moveKey(m game.Move) string {
-	// Create unique key using position and direction
-	return string(rune(m.Position.Row)) + string(rune(m.Position.Col)) + string(rune(m.Direction)) + m.Word
+func (g *Generator) prevPos(pos anchorSquare, dir game.Direction) anchorSquare {
+	if dir == game.Horizontal {
+		return anchorSquare{pos.row, pos.col - 1}
+	}
+	return anchorSquare{pos.row - 1, pos.col}
 }
 
-// positionToAnchor converts game.Position to anchorSquare
-func positionToAnchor(p game.Position) anchorSquare {
-	return anchorSquare{row: p.Row, col: p.Col}
+// moveKey identifies a placement by its start, direction, word and which
+// of its squares hold blanks, so the same word played with a blank in a
+// different place counts as a different move with its own score
+func moveKey(m game.Move) string {
+	key := string(rune(m.Position.Row)) + string(rune(m.Position.Col)) + string(rune(m.Direction)) + m.Word
+	for _, placed := range m.TilesPlaced {
+		if placed.Tile.IsBlank {
+			key += string(rune(placed.Position.Row)) + string(rune(placed.Position.Col))
+		}
+	}
+	return key
 }
 
 // anchorToPosition converts anchorSquare to game.Position
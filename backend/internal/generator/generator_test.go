@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"bytes"
 	"testing"
 	"tiletactics/backend/internal/board"
 	"tiletactics/backend/internal/gaddag"
@@ -49,6 +50,63 @@ func TestGeneratorBasic(t *testing.T) {
 	}
 }
 
+func TestGeneratorIsDeterministic(t *testing.T) {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "ACT", "TAR", "RAT", "ART", "TA", "AT", "AR"} {
+		g.Add(word)
+	}
+	rack := []game.Tile{
+		{Letter: 'T', Value: 1},
+		{Letter: 'A', Value: 1},
+		{Letter: 'C', Value: 3},
+		{Letter: game.BlankLetter, IsBlank: true},
+	}
+
+	first := New(g, board.New()).GenerateMoves(rack)
+	for run := 0; run < 5; run++ {
+		again := New(g, board.New()).GenerateMoves(rack)
+		if len(again) != len(first) {
+			t.Fatalf("run %d: %d moves, want %d", run, len(again), len(first))
+		}
+		for i := range first {
+			if moveKey(again[i]) != moveKey(first[i]) {
+				t.Fatalf("run %d: move %d is %s, want %s", run, i, again[i].Word, first[i].Word)
+			}
+		}
+	}
+}
+
+func TestGenerateMovesParallelMatchesSequential(t *testing.T) {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "CATS", "SCAT", "ACT", "ACTS", "TAR", "RAT", "ART", "ARTS", "STAR", "TA", "AT", "AS", "AR"} {
+		g.Add(word)
+	}
+	b := board.New()
+	for i, letter := range "CAT" {
+		b.SetTile(7, 7+i, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+	}
+	rack := []game.Tile{
+		{Letter: 'S', Value: 1},
+		{Letter: 'T', Value: 1},
+		{Letter: 'A', Value: 1},
+		{Letter: 'R', Value: 1},
+		{Letter: game.BlankLetter, IsBlank: true},
+	}
+
+	want := New(g, b).GenerateMoves(rack)
+	for _, workers := range []int{0, 1, 3} {
+		got := New(g, b).GenerateMovesParallel(rack, workers)
+		if len(got) != len(want) {
+			t.Fatalf("workers=%d: %d moves, want %d", workers, len(got), len(want))
+		}
+		for i := range want {
+			if moveKey(got[i]) != moveKey(want[i]) || got[i].Score != want[i].Score {
+				t.Fatalf("workers=%d: move %d is %s, want %s", workers, i, got[i].Word, want[i].Word)
+			}
+		}
+	}
+}
+
 func TestGeneratorWithExistingWord(t *testing.T) {
 	// Create GADDAG
 	g := gaddag.New()
@@ -124,3 +182,161 @@ func dirString(dir game.Direction) string {
 	}
 	return "vertical"
 }
+
+func TestGeneratorSuperBoard(t *testing.T) {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "AT"} {
+		g.Add(word)
+	}
+
+	gen := New(g, board.NewWithLayout(board.SuperLayout()))
+	moves := gen.GenerateMoves([]game.Tile{
+		{Letter: 'C', Value: 3},
+		{Letter: 'A', Value: 1},
+		{Letter: 'T', Value: 1},
+	})
+
+	if len(moves) == 0 {
+		t.Fatal("Expected to find opening moves on the super board")
+	}
+	for _, move := range moves {
+		covers := false
+		for _, placed := range move.TilesPlaced {
+			if placed.Position.Row == 10 && placed.Position.Col == 10 {
+				covers = true
+			}
+		}
+		if !covers {
+			t.Errorf("%s at (%d,%d) does not cover the super board's center", move.Word, move.Position.Row, move.Position.Col)
+		}
+	}
+}
+
+func TestGeneratorDigraphTiles(t *testing.T) {
+	dist, err := game.LoadDistribution("../../distributions/spanish.txt")
+	if err != nil {
+		t.Fatalf("LoadDistribution() error = %v", err)
+	}
+	alphabet := dist.Alphabet()
+
+	g := gaddag.New()
+	for _, word := range []string{"CHE", "AÑO", "ÑO"} {
+		encoded, err := alphabet.Encode(word)
+		if err != nil {
+			t.Fatalf("Encode(%q) error = %v", word, err)
+		}
+		g.Add(encoded)
+	}
+
+	ch, _ := alphabet.Code("CH")
+	rack := []game.Tile{dist.Tile(ch), dist.Tile('E'), dist.Tile('A'), dist.Tile('Ñ'), dist.Tile('O')}
+
+	moves := NewWithDistribution(g, board.New(), dist).GenerateMoves(rack)
+
+	found := make(map[string]game.Move)
+	for _, move := range moves {
+		found[alphabet.Decode(move.Word)] = move
+	}
+
+	che, ok := found["CHE"]
+	if !ok {
+		t.Fatalf("CHE not generated, got %v", found)
+	}
+	if len(che.TilesPlaced) != 2 {
+		t.Errorf("CHE placed %d tiles, want 2", len(che.TilesPlaced))
+	}
+
+	// Ñ is multi-byte, so scoring must walk the word by tile: A(1) Ñ(8) O(1)
+	// doubled on the centre square
+	if ano, ok := found["AÑO"]; !ok || ano.Score != 20 {
+		t.Errorf("AÑO = %+v, want a 20 point move", ano)
+	}
+}
+
+func TestGeneratorWithLoadedCompact(t *testing.T) {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "CATS", "SCAT", "AT", "ACT", "TA"} {
+		g.Add(word)
+	}
+
+	var buf bytes.Buffer
+	if _, err := g.Compact().WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := gaddag.ReadCompact(&buf)
+	if err != nil {
+		t.Fatalf("ReadCompact() error = %v", err)
+	}
+
+	rack := []game.Tile{{Letter: 'C', Value: 3}, {Letter: 'A', Value: 1}, {Letter: 'T', Value: 1}, {Letter: 'S', Value: 1}}
+	fromTrie := New(g, board.New()).GenerateMoves(rack)
+	fromFile := New(loaded, board.New()).GenerateMoves(rack)
+
+	if len(fromTrie) == 0 || len(fromTrie) != len(fromFile) {
+		t.Errorf("generated %d moves from the trie and %d from the loaded file", len(fromTrie), len(fromFile))
+	}
+}
+
+func TestGenerateExchanges(t *testing.T) {
+	gen := New(gaddag.New(), board.New())
+	rack := []game.Tile{
+		{Letter: 'A', Value: 1},
+		{Letter: 'B', Value: 3},
+		{Letter: 'A', Value: 1},
+		{Letter: game.BlankLetter, IsBlank: true},
+	}
+
+	// Two A's, a B and a blank: 3 * 2 * 2 choices, less exchanging nothing
+	exchanges := gen.GenerateExchanges(rack, game.MinBagForExchange)
+	if len(exchanges) != 11 {
+		t.Fatalf("got %d exchanges, want 11", len(exchanges))
+	}
+	seen := make(map[string]bool)
+	for _, move := range exchanges {
+		var letters []rune
+		for _, tile := range move.Exchanged {
+			letters = append(letters, tile.Letter)
+		}
+		if move.Kind != game.MoveExchange || seen[string(letters)] {
+			t.Errorf("exchange %q is repeated or has kind %d", string(letters), move.Kind)
+		}
+		seen[string(letters)] = true
+	}
+
+	if got := gen.GenerateExchanges(rack, game.MinBagForExchange-1); len(got) != 0 {
+		t.Errorf("got %d exchanges with too few tiles in the bag, want none", len(got))
+	}
+
+	all := gen.GenerateAllMoves(rack, game.MinBagForExchange)
+	if last := all[len(all)-1]; last.Kind != game.MovePass {
+		t.Errorf("last move has kind %d, want a pass", last.Kind)
+	}
+}
+
+func TestBlankPlacementsAreSeparateMoves(t *testing.T) {
+	g := gaddag.New()
+	g.Add("ZAAAZ")
+	dist := game.EnglishDistribution()
+	rack := []game.Tile{dist.Tile('Z'), dist.Tile('A'), dist.Tile('A'), dist.Tile('A'), dist.Tile(game.BlankLetter)}
+
+	gen := NewWithDistribution(g, board.New(), dist)
+	top := gen.TopMoves(rack, 1)
+	if len(top) != 1 {
+		t.Fatalf("TopMoves() found %d moves, want 1", len(top))
+	}
+
+	// The natural Z on a double-letter square: (20 + 3) * 2
+	if top[0].Score != 46 {
+		t.Errorf("best move %s scores %d, want 46", top[0].Notation(dist.Alphabet()), top[0].Score)
+	}
+
+	scores := make(map[int]bool)
+	for _, move := range gen.GenerateMoves(rack) {
+		if move.Word == "ZAAAZ" && move.Position == (game.Position{Row: 7, Col: 3}) && move.Direction == game.Horizontal {
+			scores[move.Score] = true
+		}
+	}
+	if !scores[26] || !scores[46] {
+		t.Errorf("8D ZAAAZ scored %v, want both 26 and 46", scores)
+	}
+}
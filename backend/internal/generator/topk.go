@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"container/heap"
+	"sort"
+	"tiletactics/backend/internal/game"
+)
+
+// TopK keeps the k best moves offered to it, by any ranking, without
+// keeping the rest. Its Add fits inside a GenerateFunc callback.
+type TopK struct {
+	k     int
+	next  int // Order the next move was offered in, to break ties
+	items topKHeap
+}
+
+// NewTopK creates a TopK holding at most k moves
+func NewTopK(k int) *TopK {
+	return &TopK{k: k, items: make(topKHeap, 0, k)}
+}
+
+// Add offers a move with the value it is ranked by. Of moves with equal
+// value, the one offered first is kept.
+func (t *TopK) Add(move game.Move, value float64) {
+	if t.k <= 0 {
+		return
+	}
+
+	item := topKItem{move: move, value: value, order: t.next}
+	t.next++
+
+	if len(t.items) < t.k {
+		heap.Push(&t.items, item)
+		return
+	}
+	if t.items.less(t.items[0], item) {
+		t.items[0] = item
+		heap.Fix(&t.items, 0)
+	}
+}
+
+// Len returns the number of moves held
+func (t *TopK) Len() int {
+	return len(t.items)
+}
+
+// Moves returns the moves held, best first
+func (t *TopK) Moves() []game.Move {
+	ranked := t.Ranked()
+	moves := make([]game.Move, len(ranked))
+	for i, r := range ranked {
+		moves[i] = r.Move
+	}
+	return moves
+}
+
+// RankedMove is a move held by a TopK with the value it was ranked by
+type RankedMove struct {
+	Move  game.Move
+	Value float64
+}
+
+// Ranked returns the moves held with their values, best first
+func (t *TopK) Ranked() []RankedMove {
+	items := make([]topKItem, len(t.items))
+	copy(items, t.items)
+	sort.Slice(items, func(i, j int) bool {
+		return t.items.less(items[j], items[i])
+	})
+
+	ranked := make([]RankedMove, len(items))
+	for i, item := range items {
+		ranked[i] = RankedMove{Move: item.move, Value: item.value}
+	}
+	return ranked
+}
+
+type topKItem struct {
+	move  game.Move
+	value float64
+	order int
+}
+
+// topKHeap is a min-heap with the worst move held at the top
+type topKHeap []topKItem
+
+// less reports whether a ranks below b; later moves lose ties
+func (h topKHeap) less(a, b topKItem) bool {
+	if a.value != b.value {
+		return a.value < b.value
+	}
+	return a.order > b.order
+}
+
+func (h topKHeap) Len() int           { return len(h) }
+func (h topKHeap) Less(i, j int) bool { return h.less(h[i], h[j]) }
+func (h topKHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *topKHeap) Push(x any) {
+	*h = append(*h, x.(topKItem))
+}
+
+func (h *topKHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+)
+
+func TestTopK(t *testing.T) {
+	top := NewTopK(3)
+	for i, value := range []float64{5, 1, 9, 5, 7, 2} {
+		top.Add(game.Move{Word: string(rune('A' + i))}, value)
+	}
+
+	var words string
+	for _, move := range top.Moves() {
+		words += move.Word
+	}
+	// 9 (C), 7 (E), then the first of the two 5s (A)
+	if words != "CEA" {
+		t.Errorf("Moves() = %q, want CEA", words)
+	}
+}
+
+func TestTopMovesMatchesGenerateMoves(t *testing.T) {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "ACT", "TAR", "RAT", "ART", "CART", "TA", "AT", "AR"} {
+		g.Add(word)
+	}
+	b := boardWithCat()
+	rack := []game.Tile{
+		{Letter: 'R', Value: 1},
+		{Letter: 'A', Value: 1},
+		{Letter: 'T', Value: 1},
+		{Letter: game.BlankLetter, IsBlank: true},
+	}
+
+	all := New(g, b).GenerateMoves(rack)
+	top := New(g, b).TopMoves(rack, 5)
+	if len(all) < 5 || len(top) != 5 {
+		t.Fatalf("got %d moves in all and %d in top, want at least 5 and 5", len(all), len(top))
+	}
+	for i := range top {
+		if moveKey(top[i]) != moveKey(all[i]) || top[i].Score != all[i].Score {
+			t.Errorf("top move %d is %s (%d), want %s (%d)", i, top[i].Word, top[i].Score, all[i].Word, all[i].Score)
+		}
+	}
+}
+
+func TestGenerateFuncStopsEarly(t *testing.T) {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "ACT", "TA", "AT"} {
+		g.Add(word)
+	}
+	rack := []game.Tile{{Letter: 'C', Value: 3}, {Letter: 'A', Value: 1}, {Letter: 'T', Value: 1}}
+
+	calls := 0
+	New(g, board.New()).GenerateFunc(rack, func(move game.Move) bool {
+		calls++
+		if move.Score == 0 {
+			t.Errorf("move %s was not scored", move.Word)
+		}
+		return calls < 2
+	})
+	if calls != 2 {
+		t.Errorf("fn called %d times, want 2", calls)
+	}
+}
@@ -5,51 +5,19 @@ import (
 	"tiletactics/backend/internal/game"
 )
 
-// extendRight continues building a word to the right/down from current position
-func (g *Generator) extendRight(
-	node *gaddag.Node,
+// extendLeft places the part of a word from the anchor backwards. GADDAG
+// paths spell this part reversed, so each step moves one square left/up.
+func (g *Generator) extendLeft(
+	node uint32,
 	pos anchorSquare,
+	anchor anchorSquare,
 	word string,
 	tilesPlaced []game.PlacedTile,
-	rackMap map[rune]int,
-	blanks int,
+	rack *game.Rack,
 	dir game.Direction,
-	anchorSeen bool,
-	moves *[]game.Move,
+	s *search,
 ) {
-	// Check if current position is out of bounds
-	if pos.row >= game.BoardSize || pos.col >= game.BoardSize {
-		// Check if we can terminate here
-		if anchorSeen && len(tilesPlaced) > 0 {
-			if g.gaddag.Contains(word) {
-				move := game.Move{
-					Word:        word,
-					Position:    g.findMoveStart(word, tilesPlaced, dir),
-					Direction:   dir,
-					TilesPlaced: tilesPlaced,
-				}
-
-				// Double-check all perpendicular words before adding the move
-				validMove := true
-				for _, placed := range tilesPlaced {
-					perpWord := g.getPerpendicularWord(
-						placed.Position.Row,
-						placed.Position.Col,
-						&placed.Tile,
-						dir,
-					)
-
-					if len(perpWord) > 1 && !g.gaddag.Contains(perpWord) {
-						validMove = false
-						break
-					}
-				}
-
-				if validMove {
-					*moves = append(*moves, move)
-				}
-			}
-		}
+	if s.stopped {
 		return
 	}
 
@@ -58,350 +26,218 @@ func (g *Generator) extendRight(
 	if existingTile != nil {
 		// Square already has a tile - must use it
 		letter := existingTile.Letter
-		if nextNode := node.GetEdge(letter); nextNode != nil {
-			nextPos := g.nextPos(pos, dir)
-			isAnchor := g.board.IsAnchor(pos.row, pos.col)
-			g.extendRight(
-				nextNode,
-				nextPos,
-				word+string(letter),
-				tilesPlaced,
-				rackMap,
-				blanks,
-				dir,
-				anchorSeen || isAnchor,
-				moves,
-			)
+		if nextNode, ok := g.gaddag.Edge(node, letter); ok {
+			g.leftFrom(nextNode, pos, anchor, string(letter)+word, tilesPlaced, rack, dir, s)
 		}
-	} else {
-		// Empty square - check if we can terminate here
-		if anchorSeen && len(tilesPlaced) > 0 {
-			if g.gaddag.Contains(word) {
-				move := game.Move{
-					Word:        word,
-					Position:    g.findMoveStart(word, tilesPlaced, dir),
-					Direction:   dir,
-					TilesPlaced: tilesPlaced,
-				}
-
-				// Double-check all perpendicular words before adding the move
-				validMove := true
-				for _, placed := range tilesPlaced {
-					perpWord := g.getPerpendicularWord(
-						placed.Position.Row,
-						placed.Position.Col,
-						&placed.Tile,
-						dir,
-					)
+		return
+	}
 
-					if len(perpWord) > 1 && !g.gaddag.Contains(perpWord) {
-						validMove = false
-						break
-					}
-				}
+	// Empty squares before the anchor must not be anchors themselves,
+	// otherwise the same move would also be found from that anchor
+	if pos != anchor && g.isAnchor(pos.row, pos.col) {
+		return
+	}
 
-				if validMove {
-					*moves = append(*moves, move)
-				}
+	// Try each letter in rack
+	for i, letter := range g.dist.Letters {
+		if rack.CountAt(i) > 0 && g.allows(pos, i, dir) {
+			if nextNode, ok := g.gaddag.Edge(node, letter); ok {
+				// Use the tile
+				rack.RemoveAt(i)
+				newPlaced := prependPlaced(tilesPlaced, game.PlacedTile{
+					Position: game.Position{Row: pos.row, Col: pos.col},
+					Tile:     game.Tile{Letter: letter, Value: g.dist.Value(letter)},
+				})
+
+				g.leftFrom(nextNode, pos, anchor, string(letter)+word, newPlaced, rack, dir, s)
+
+				// Return the tile
+				rack.AddAt(i)
 			}
 		}
+	}
 
-		// Try placing tiles from rack
-		isAnchor := g.board.IsAnchor(pos.row, pos.col)
-
-		// Try each letter in rack
-		for letter, count := range rackMap {
-			if count > 0 && g.isValidCrossWord(pos, letter, dir) {
-				if nextNode := node.GetEdge(letter); nextNode != nil {
-					// Use the tile
-					rackMap[letter]--
-					newPlaced := append(tilesPlaced, game.PlacedTile{
+	// Try blanks
+	if rack.Blanks() > 0 {
+		for i, letter := range g.dist.Letters {
+			if g.allows(pos, i, dir) {
+				if nextNode, ok := g.gaddag.Edge(node, letter); ok {
+					// Use blank as this letter
+					rack.RemoveBlank()
+					newPlaced := prependPlaced(tilesPlaced, game.PlacedTile{
 						Position: game.Position{Row: pos.row, Col: pos.col},
-						Tile:     game.Tile{Letter: letter, Value: game.TileValues[letter]},
+						Tile:     game.Tile{Letter: letter, Value: 0, IsBlank: true},
 					})
 
-					nextPos := g.nextPos(pos, dir)
-					g.extendRight(
-						nextNode,
-						nextPos,
-						word+string(letter),
-						newPlaced,
-						rackMap,
-						blanks,
-						dir,
-						anchorSeen || isAnchor,
-						moves,
-					)
+					g.leftFrom(nextNode, pos, anchor, string(letter)+word, newPlaced, rack, dir, s)
 
-					// Return the tile
-					rackMap[letter]++
+					// Return the blank
+					rack.AddBlank()
 				}
 			}
 		}
+	}
+}
 
-		// Try blanks
-		if blanks > 0 {
-			for letter := 'A'; letter <= 'Z'; letter++ {
-				if g.isValidCrossWord(pos, letter, dir) {
-					if nextNode := node.GetEdge(letter); nextNode != nil {
-						// Use blank as this letter
-						newPlaced := append(tilesPlaced, game.PlacedTile{
-							Position: game.Position{Row: pos.row, Col: pos.col},
-							Tile:     game.Tile{Letter: letter, Value: 0, IsBlank: true},
-						})
+// leftFrom continues once pos has been filled: either further back, or, if
+// the word can start at pos, across the separator to the squares after the anchor
+func (g *Generator) leftFrom(
+	node uint32,
+	pos anchorSquare,
+	anchor anchorSquare,
+	word string,
+	tilesPlaced []game.PlacedTile,
+	rack *game.Rack,
+	dir game.Direction,
+	s *search,
+) {
+	prev := g.prevPos(pos, dir)
+	if g.board.InBounds(prev.row, prev.col) {
+		g.extendLeft(node, prev, anchor, word, tilesPlaced, rack, dir, s)
 
-						nextPos := g.nextPos(pos, dir)
-						g.extendRight(
-							nextNode,
-							nextPos,
-							word+string(letter),
-							newPlaced,
-							rackMap,
-							blanks-1,
-							dir,
-							anchorSeen || isAnchor,
-							moves,
-						)
-					}
-				}
-			}
+		// A tile just before pos belongs to the word, so it cannot start here
+		if g.board.GetTile(prev.row, prev.col) != nil {
+			return
 		}
+	}
 
-		// Try extending without placing a tile here (skip to separator)
-		if !isAnchor {
-			if separatorNode := node.GetEdge(gaddag.Separator); separatorNode != nil {
-				g.extendAfterSeparator(
-					separatorNode,
-					pos,
-					word,
-					tilesPlaced,
-					rackMap,
-					blanks,
-					dir,
-					anchorSeen,
-					moves,
-				)
-			}
-		}
+	if separatorNode, ok := g.gaddag.Edge(node, gaddag.Separator); ok {
+		g.extendRight(
+			separatorNode,
+			g.nextPos(anchor, dir),
+			pos,
+			word,
+			tilesPlaced,
+			rack,
+			dir,
+			s,
+		)
 	}
 }
 
-// extendAfterSeparator handles the part after seeing the separator in GADDAG
-func (g *Generator) extendAfterSeparator(
-	node *gaddag.Node,
+// extendRight continues building a word to the right/down of the anchor
+// after the separator, finishing it wherever the GADDAG allows
+func (g *Generator) extendRight(
+	node uint32,
 	pos anchorSquare,
+	start anchorSquare,
 	word string,
 	tilesPlaced []game.PlacedTile,
-	rackMap map[rune]int,
-	blanks int,
+	rack *game.Rack,
 	dir game.Direction,
-	anchorSeen bool,
-	moves *[]game.Move,
+	s *search,
 ) {
-	// After separator, we place tiles going backwards (building prefix)
-	if pos.row < 0 || pos.col < 0 {
+	if s.stopped {
 		return
 	}
 
 	existingTile := g.board.GetTile(pos.row, pos.col)
 
 	if existingTile != nil {
-		// Must use existing tile
+		// Square already has a tile - must use it
 		letter := existingTile.Letter
-		if nextNode := node.GetEdge(letter); nextNode != nil {
-			prevPos := g.prevPos(pos, dir)
-			// Prepend letter to word
-			g.extendAfterSeparator(
+		if nextNode, ok := g.gaddag.Edge(node, letter); ok {
+			g.extendRight(
 				nextNode,
-				prevPos,
-				string(letter)+word,
+				g.nextPos(pos, dir),
+				start,
+				word+string(letter),
 				tilesPlaced,
-				rackMap,
-				blanks,
+				rack,
 				dir,
-				anchorSeen,
-				moves,
+				s,
 			)
 		}
-	} else {
-		// Check if we can end here (forms valid word)
-		if anchorSeen && len(tilesPlaced) > 0 {
-			if g.gaddag.Contains(word) {
-				move := game.Move{
-					Word:        word,
-					Position:    game.Position{Row: pos.row, Col: pos.col},
-					Direction:   dir,
-					TilesPlaced: tilesPlaced,
-				}
-				if dir == game.Vertical {
-					move.Position.Row = pos.row
-				} else {
-					move.Position.Col = pos.col
-				}
+		return
+	}
 
-				// Double-check all perpendicular words before adding the move
-				validMove := true
-				for _, placed := range tilesPlaced {
-					perpWord := g.getPerpendicularWord(
-						placed.Position.Row,
-						placed.Position.Col,
-						&placed.Tile,
-						dir,
-					)
+	// Empty or off-board square - check if we can terminate here
+	if len(tilesPlaced) > 0 && g.gaddag.IsTerminal(node) {
+		// Cross-checks already guarantee every perpendicular word
+		s.emit(game.Move{
+			Word:        word,
+			Position:    anchorToPosition(start),
+			Direction:   dir,
+			TilesPlaced: tilesPlaced,
+		})
+	}
 
-					if len(perpWord) > 1 && !g.gaddag.Contains(perpWord) {
-						validMove = false
-						break
-					}
-				}
+	if !g.board.InBounds(pos.row, pos.col) {
+		return
+	}
 
-				if validMove {
-					*moves = append(*moves, move)
-				}
+	// Try each letter in rack
+	for i, letter := range g.dist.Letters {
+		if rack.CountAt(i) > 0 && g.allows(pos, i, dir) {
+			if nextNode, ok := g.gaddag.Edge(node, letter); ok {
+				// Use the tile
+				rack.RemoveAt(i)
+				newPlaced := appendPlaced(tilesPlaced, game.PlacedTile{
+					Position: game.Position{Row: pos.row, Col: pos.col},
+					Tile:     game.Tile{Letter: letter, Value: g.dist.Value(letter)},
+				})
+
+				g.extendRight(
+					nextNode,
+					g.nextPos(pos, dir),
+					start,
+					word+string(letter),
+					newPlaced,
+					rack,
+					dir,
+					s,
+				)
+
+				// Return the tile
+				rack.AddAt(i)
 			}
 		}
+	}
 
-		// Try placing tiles from rack going backwards
-		for letter, count := range rackMap {
-			if count > 0 && g.isValidCrossWord(pos, letter, dir) {
-				if nextNode := node.GetEdge(letter); nextNode != nil {
-					rackMap[letter]--
-
-					// Place tile at current position
-					newPlaced := make([]game.PlacedTile, len(tilesPlaced)+1)
-					newPlaced[0] = game.PlacedTile{
+	// Try blanks
+	if rack.Blanks() > 0 {
+		for i, letter := range g.dist.Letters {
+			if g.allows(pos, i, dir) {
+				if nextNode, ok := g.gaddag.Edge(node, letter); ok {
+					// Use blank as this letter
+					rack.RemoveBlank()
+					newPlaced := appendPlaced(tilesPlaced, game.PlacedTile{
 						Position: game.Position{Row: pos.row, Col: pos.col},
-						Tile:     game.Tile{Letter: letter, Value: game.TileValues[letter]},
-					}
-					copy(newPlaced[1:], tilesPlaced)
+						Tile:     game.Tile{Letter: letter, Value: 0, IsBlank: true},
+					})
 
-					prevPos := g.prevPos(pos, dir)
-					g.extendAfterSeparator(
+					g.extendRight(
 						nextNode,
-						prevPos,
-						string(letter)+word,
+						g.nextPos(pos, dir),
+						start,
+						word+string(letter),
 						newPlaced,
-						rackMap,
-						blanks,
+						rack,
 						dir,
-						anchorSeen,
-						moves,
+						s,
 					)
 
-					rackMap[letter]++
-				}
-			}
-		}
-
-		// Try blanks
-		if blanks > 0 {
-			for letter := 'A'; letter <= 'Z'; letter++ {
-				if g.isValidCrossWord(pos, letter, dir) {
-					if nextNode := node.GetEdge(letter); nextNode != nil {
-						newPlaced := make([]game.PlacedTile, len(tilesPlaced)+1)
-						newPlaced[0] = game.PlacedTile{
-							Position: game.Position{Row: pos.row, Col: pos.col},
-							Tile:     game.Tile{Letter: letter, Value: 0, IsBlank: true},
-						}
-						copy(newPlaced[1:], tilesPlaced)
-
-						prevPos := g.prevPos(pos, dir)
-						g.extendAfterSeparator(
-							nextNode,
-							prevPos,
-							string(letter)+word,
-							newPlaced,
-							rackMap,
-							blanks-1,
-							dir,
-							anchorSeen,
-							moves,
-						)
-					}
+					// Return the blank
+					rack.AddBlank()
 				}
 			}
 		}
 	}
 }
 
-// isValidCrossWord checks if placing a letter forms valid perpendicular words
-func (g *Generator) isValidCrossWord(pos anchorSquare, letter rune, dir game.Direction) bool {
-	// Determine perpendicular direction
-	crossDir := game.Vertical
-	if dir == game.Vertical {
-		crossDir = game.Horizontal
-	}
-
-	// Check if placing this letter would form a perpendicular word
-	prevPos := g.prevPos(pos, crossDir)
-	nextPos := g.nextPos(pos, crossDir)
-
-	// If no tiles adjacent in perpendicular direction, no cross word formed
-	hasPrev := prevPos.row >= 0 && prevPos.col >= 0 && g.board.GetTile(prevPos.row, prevPos.col) != nil
-	hasNext := nextPos.row < game.BoardSize && nextPos.col < game.BoardSize && g.board.GetTile(nextPos.row, nextPos.col) != nil
-
-	if !hasPrev && !hasNext {
-		return true // No cross word formed, so valid
-	}
-
-	// Build the cross word
-	crossWord := string(letter)
-
-	// Add letters before
-	checkPos := prevPos
-	for checkPos.row >= 0 && checkPos.col >= 0 {
-		tile := g.board.GetTile(checkPos.row, checkPos.col)
-		if tile == nil {
-			break
-		}
-		crossWord = string(tile.Letter) + crossWord
-		checkPos = g.prevPos(checkPos, crossDir)
-	}
-
-	// Add letters after
-	checkPos = nextPos
-	for checkPos.row < game.BoardSize && checkPos.col < game.BoardSize {
-		tile := g.board.GetTile(checkPos.row, checkPos.col)
-		if tile == nil {
-			break
-		}
-		crossWord = crossWord + string(tile.Letter)
-		checkPos = g.nextPos(checkPos, crossDir)
-	}
-
-	// Check if cross word is valid
-	return g.gaddag.Contains(crossWord)
+// prependPlaced returns a new slice with tile in front, leaving tilesPlaced
+// untouched for the caller's other branches
+func prependPlaced(tilesPlaced []game.PlacedTile, tile game.PlacedTile) []game.PlacedTile {
+	newPlaced := make([]game.PlacedTile, len(tilesPlaced)+1)
+	newPlaced[0] = tile
+	copy(newPlaced[1:], tilesPlaced)
+	return newPlaced
 }
 
-// findMoveStart finds the starting position of a move
-func (g *Generator) findMoveStart(word string, tilesPlaced []game.PlacedTile, dir game.Direction) game.Position {
-	if len(word) == 0 {
-		return game.Position{}
-	}
-
-	// If no tiles placed, word must already exist on board
-	if len(tilesPlaced) == 0 {
-		return game.Position{}
-	}
-
-	// Find the position of the first tile placed
-	firstPlaced := tilesPlaced[0].Position
-
-	// Count existing tiles before first placed tile
-	pos := positionToAnchor(firstPlaced)
-	existingBefore := 0
-
-	for {
-		prev := g.prevPos(pos, dir)
-		if prev.row < 0 || prev.col < 0 {
-			break
-		}
-		if g.board.GetTile(prev.row, prev.col) == nil {
-			break
-		}
-		existingBefore++
-		pos = prev
-	}
-
-	return anchorToPosition(pos)
+// appendPlaced returns a new slice with tile at the end. It always copies,
+// as a shared backing array would let sibling branches overwrite each other.
+func appendPlaced(tilesPlaced []game.PlacedTile, tile game.PlacedTile) []game.PlacedTile {
+	newPlaced := make([]game.PlacedTile, len(tilesPlaced), len(tilesPlaced)+1)
+	copy(newPlaced, tilesPlaced)
+	return append(newPlaced, tile)
 }
@@ -0,0 +1,224 @@
+package inference
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/evaluator"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+	"tiletactics/backend/internal/scorer"
+)
+
+const (
+	// DefaultSamples is how many racks are tried when Config leaves it unset
+	DefaultSamples = 500
+
+	// DefaultTemperature is how many points of evaluation a play may give
+	// up before its likelihood falls by a factor of e, when Config leaves it unset
+	DefaultTemperature = 4.0
+
+	rackSize = 7
+)
+
+// Position is the state the opponent moved from, as we saw it
+type Position struct {
+	Board  *board.Board // Before their move
+	Move   game.Move    // What they played; for an exchange only the number of tiles matters
+	Unseen map[rune]int // Bag plus their rack before the move, with '?' for blanks
+}
+
+// Config controls how much work inference does
+type Config struct {
+	Samples     int     // Racks drawn from the unseen tiles
+	Temperature float64 // How forgiving the likelihood is of plays below the best
+	Seed        int64
+}
+
+// Leave is a set of tiles the opponent may have kept, with its probability
+type Leave struct {
+	Tiles  []game.Tile
+	Weight float64
+}
+
+// Inferrer works out what the opponent kept from the move they chose. A
+// rack is likely in proportion to how close their move comes to the best
+// move our evaluator finds for that rack.
+type Inferrer struct {
+	lexicon *gaddag.Compact
+	dist    *game.LetterDistribution
+	weights evaluator.Weights
+	leaves  *evaluator.LeaveTable
+}
+
+// New creates an inferrer that judges racks with the default evaluator
+func New(lexicon gaddag.Lexicon, dist *game.LetterDistribution) *Inferrer {
+	return &Inferrer{
+		lexicon: lexicon.Compact(),
+		dist:    dist,
+		weights: evaluator.DefaultWeights,
+	}
+}
+
+// SetLeaves judges the opponent's racks with a trained leave table, for
+// opponents thought to value their leaves the way the table does
+func (inf *Inferrer) SetLeaves(leaves *evaluator.LeaveTable) {
+	inf.leaves = leaves
+}
+
+// Infer draws racks that contain the opponent's move from the unseen
+// tiles and returns what they would have kept, weighted by how well the
+// move fits each rack. Weights sum to one, most likely first. It returns
+// nil when no rack fits, such as for a word our lexicon does not know.
+func (inf *Inferrer) Infer(pos Position, cfg Config) []Leave {
+	if cfg.Samples <= 0 {
+		cfg.Samples = DefaultSamples
+	}
+	if cfg.Temperature <= 0 {
+		cfg.Temperature = DefaultTemperature
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	// Tiles known to have been on their rack, and the rest to draw from
+	var played []game.Tile
+	if pos.Move.Kind == game.MovePlace {
+		for _, tile := range pos.Move.TilesUsed() {
+			played = append(played, inf.rackTile(tile))
+		}
+	}
+	unseen, _ := game.NewRackFromCounts(inf.dist, pos.Unseen)
+	unseen.RemoveTiles(played)
+	pool := unseen.Tiles()
+	bagLen := len(pool) + len(played) - rackSize
+	n := min(rackSize-len(played), len(pool))
+
+	weights := make(map[string]float64)
+	total := 0.0
+	kept := make(map[string][]game.Tile)
+	fits := make(map[string]fit) // By rack, as short racks repeat often
+
+	for i := 0; i < cfg.Samples; i++ {
+		rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+		rack := append(append([]game.Tile(nil), played...), pool[:n]...)
+
+		rackKey := evaluator.LeaveKey(rack, inf.dist)
+		f, ok := fits[rackKey]
+		if !ok {
+			f = inf.judge(pos, rack, bagLen, cfg.Temperature)
+			fits[rackKey] = f
+		}
+		if f.likelihood == 0 {
+			continue
+		}
+
+		key := evaluator.LeaveKey(f.leave, inf.dist)
+		weights[key] += f.likelihood
+		total += f.likelihood
+		kept[key] = f.leave
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	leaves := make([]Leave, 0, len(weights))
+	for key, w := range weights {
+		leaves = append(leaves, Leave{Tiles: kept[key], Weight: w / total})
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		if leaves[i].Weight != leaves[j].Weight {
+			return leaves[i].Weight > leaves[j].Weight
+		}
+		return evaluator.LeaveKey(leaves[i].Tiles, inf.dist) < evaluator.LeaveKey(leaves[j].Tiles, inf.dist)
+	})
+	return leaves
+}
+
+// fit is how likely the opponent's move is from one rack, and what it keeps
+type fit struct {
+	likelihood float64
+	leave      []game.Tile
+}
+
+// judge rates the opponent's move from rack. For an exchange, the tiles
+// kept are those of the best exchange of the same number of tiles.
+func (inf *Inferrer) judge(pos Position, rack []game.Tile, bagLen int, temperature float64) fit {
+	// From their side, everything but this rack is unseen
+	bag, _ := game.NewRackFromCounts(inf.dist, pos.Unseen)
+	bag.RemoveTiles(rack)
+	eval := evaluator.NewWithDistribution(bag.Counts(), inf.weights, inf.dist)
+	if inf.leaves != nil {
+		eval.SetLeaves(inf.leaves)
+	}
+
+	best := math.Inf(-1)
+	chosen := math.Inf(-1)
+	var leave []game.Tile
+
+	gen := generator.NewWithDistribution(inf.lexicon, pos.Board, inf.dist)
+	gen.GenerateFunc(rack, func(move game.Move) bool {
+		_, value := eval.Evaluate(move, rack)
+		best = math.Max(best, value)
+		if pos.Move.Kind == game.MovePlace && samePlacement(move, pos.Move) {
+			// Rate the play as made, with blanks where the opponent put them
+			move.TilesPlaced = pos.Move.TilesPlaced
+			move.Score = scorer.NewWithDistribution(pos.Board, inf.dist).ScoreMove(move)
+			_, chosen = eval.Evaluate(move, rack)
+		}
+		return true
+	})
+
+	for _, move := range gen.GenerateExchanges(rack, bagLen) {
+		move, value := eval.Evaluate(move, rack)
+		best = math.Max(best, value)
+		if pos.Move.Kind == game.MoveExchange && len(move.Exchanged) == len(pos.Move.Exchanged) && value > chosen {
+			chosen, leave = value, move.Leave
+		}
+	}
+
+	_, value := eval.Evaluate(game.Move{Kind: game.MovePass}, rack)
+	best = math.Max(best, value)
+	if pos.Move.Kind == game.MovePass {
+		chosen = value
+	}
+
+	if math.IsInf(chosen, -1) {
+		return fit{}
+	}
+	kept, _ := game.NewRack(inf.dist, leave)
+	if pos.Move.Kind != game.MoveExchange {
+		kept, _ = game.NewRack(inf.dist, rack)
+		kept.RemoveTiles(pos.Move.TilesUsed())
+	}
+	return fit{likelihood: math.Exp((chosen - best) / temperature), leave: kept.Tiles()}
+}
+
+// rackTile returns a played tile as it sat on the rack, undoing a blank's designation
+func (inf *Inferrer) rackTile(tile game.Tile) game.Tile {
+	if tile.IsBlank {
+		return inf.dist.Tile(game.BlankLetter)
+	}
+	return inf.dist.Tile(tile.Letter)
+}
+
+// samePlacement reports whether two placements make the same word from the
+// same start in the same direction, covering the same squares with the same
+// letters. A blank and a natural tile count as the same, as the play is
+// rated with the tiles the opponent actually put down.
+func samePlacement(a, b game.Move) bool {
+	if a.Word != b.Word || a.Position != b.Position || a.Direction != b.Direction || len(a.TilesPlaced) != len(b.TilesPlaced) {
+		return false
+	}
+	covered := make(map[game.Position]rune, len(a.TilesPlaced))
+	for _, placed := range a.TilesPlaced {
+		covered[placed.Position] = placed.Tile.Letter
+	}
+	for _, placed := range b.TilesPlaced {
+		if letter, ok := covered[placed.Position]; !ok || letter != placed.Tile.Letter {
+			return false
+		}
+	}
+	return true
+}
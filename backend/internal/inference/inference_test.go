@@ -0,0 +1,148 @@
+package inference
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+)
+
+func newTestInferrer() *Inferrer {
+	g := gaddag.New()
+	for _, word := range []string{
+		"CAT", "CATS", "SCAT", "ACT", "ACTS", "AT", "AS", "TA", "TAS", "SAT",
+		"TEA", "TEAS", "EAT", "EATS", "SEAT", "ATE", "ETA", "ETAS", "EAST",
+	} {
+		g.Add(word)
+	}
+	return New(g, game.EnglishDistribution())
+}
+
+func testBoard() *board.Board {
+	b := board.New()
+	for i, letter := range "CAT" {
+		b.SetTile(7, 7+i, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+	}
+	return b
+}
+
+func TestInferPlacement(t *testing.T) {
+	inf := newTestInferrer()
+	pos := Position{
+		Board: testBoard(),
+		Move: game.Move{
+			Word:      "CATS",
+			Position:  game.Position{Row: 7, Col: 7},
+			Direction: game.Horizontal,
+			TilesPlaced: []game.PlacedTile{
+				{Position: game.Position{Row: 7, Col: 10}, Tile: game.Tile{Letter: 'S', Value: 1}},
+			},
+		},
+		Unseen: map[rune]int{'A': 5, 'E': 5, 'S': 3, 'T': 4, 'Q': 2, 'V': 3, 'W': 2, '?': 1},
+	}
+	cfg := Config{Samples: 60, Seed: 3}
+
+	leaves := inf.Infer(pos, cfg)
+	if len(leaves) == 0 {
+		t.Fatal("no leaves inferred")
+	}
+
+	total := 0.0
+	for i, leave := range leaves {
+		if len(leave.Tiles) != 6 {
+			t.Errorf("leave %d has %d tiles, want 6", i, len(leave.Tiles))
+		}
+		if i > 0 && leave.Weight > leaves[i-1].Weight {
+			t.Errorf("leaves not sorted by weight at %d", i)
+		}
+		total += leave.Weight
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("weights sum to %v, want 1", total)
+	}
+
+	if again := inf.Infer(pos, cfg); !reflect.DeepEqual(again, leaves) {
+		t.Error("same seed gave different leaves")
+	}
+}
+
+func TestJudgePass(t *testing.T) {
+	inf := newTestInferrer()
+	pos := Position{
+		Board:  testBoard(),
+		Move:   game.Move{Kind: game.MovePass},
+		Unseen: map[rune]int{'Q': 2, 'V': 3, 'W': 2, 'S': 1, 'E': 1, 'A': 1, 'T': 1},
+	}
+	dist := game.EnglishDistribution()
+	rack := func(letters string) []game.Tile {
+		var tiles []game.Tile
+		for _, letter := range letters {
+			tiles = append(tiles, dist.Tile(letter))
+		}
+		return tiles
+	}
+
+	// Nothing plays from this rack, so passing was the best move
+	stuck := inf.judge(pos, rack("QQVVVWW"), 0, DefaultTemperature)
+	if stuck.likelihood != 1 {
+		t.Errorf("pass from a rack with no plays has likelihood %v, want 1", stuck.likelihood)
+	}
+
+	// Passing with SEAT on the rack turns down good plays
+	playable := inf.judge(pos, rack("SEATQVW"), 0, DefaultTemperature)
+	if playable.likelihood >= stuck.likelihood {
+		t.Errorf("pass from SEATQVW has likelihood %v, want less than %v", playable.likelihood, stuck.likelihood)
+	}
+	if len(playable.leave) != 7 {
+		t.Errorf("pass kept %d tiles, want 7", len(playable.leave))
+	}
+}
+
+func TestJudgeBlankPlay(t *testing.T) {
+	inf := newTestInferrer()
+	dist := game.EnglishDistribution()
+	blankS := dist.Tile('?')
+	blankS.Letter = 'S'
+	pos := Position{
+		Board: testBoard(),
+		Move: game.Move{
+			Word:     "CATS",
+			Position: game.Position{Row: 7, Col: 7},
+			TilesPlaced: []game.PlacedTile{
+				{Position: game.Position{Row: 7, Col: 10}, Tile: blankS},
+			},
+		},
+		Unseen: map[rune]int{'Q': 2, 'V': 2, 'S': 1, '?': 1},
+	}
+
+	// The rack could make CATS with its natural S too; the blank play must
+	// still be found among the generated moves
+	rack := []game.Tile{dist.Tile('?'), dist.Tile('S'), dist.Tile('Q'), dist.Tile('Q'), dist.Tile('V'), dist.Tile('V')}
+	judged := inf.judge(pos, rack, 0, DefaultTemperature)
+	if judged.likelihood == 0 {
+		t.Fatal("blank CATS from a rack holding S and a blank has likelihood 0")
+	}
+	kept, _ := game.NewRack(dist, judged.leave)
+	if want := map[rune]int{'S': 1, 'Q': 2, 'V': 2}; !reflect.DeepEqual(kept.Counts(), want) {
+		t.Errorf("leave = %v, want the natural S kept", judged.leave)
+	}
+}
+
+func TestInferUnknownWord(t *testing.T) {
+	inf := newTestInferrer()
+	pos := Position{
+		Board: testBoard(),
+		Move: game.Move{
+			Word: "CATX",
+			TilesPlaced: []game.PlacedTile{
+				{Position: game.Position{Row: 7, Col: 10}, Tile: game.Tile{Letter: 'X', Value: 8}},
+			},
+		},
+		Unseen: map[rune]int{'A': 4, 'E': 4, 'X': 1, 'T': 3},
+	}
+	if leaves := inf.Infer(pos, Config{Samples: 10}); leaves != nil {
+		t.Errorf("got %d leaves for a word outside the lexicon, want none", len(leaves))
+	}
+}
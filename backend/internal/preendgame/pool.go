@@ -0,0 +1,137 @@
+package preendgame
+
+import "tiletactics/backend/internal/game"
+
+// pool counts tiles by distribution letter index, with blanks last
+type pool []int
+
+// poolOf counts the tiles in a letter count map, with '?' for blanks
+func (s *Solver) poolOf(counts map[rune]int) pool {
+	p := make(pool, len(s.dist.Letters)+1)
+	for letter, n := range counts {
+		if letter == game.BlankLetter {
+			p[len(p)-1] += n
+		} else if i, ok := s.dist.Index(letter); ok {
+			p[i] += n
+		}
+	}
+	return p
+}
+
+func (p pool) len() int {
+	total := 0
+	for _, n := range p {
+		total += n
+	}
+	return total
+}
+
+func (p pool) clone() pool {
+	return append(pool(nil), p...)
+}
+
+func (p pool) plus(q pool) pool {
+	sum := p.clone()
+	for i, n := range q {
+		sum[i] += n
+	}
+	return sum
+}
+
+func (p pool) minus(q pool) pool {
+	diff := p.clone()
+	for i, n := range q {
+		diff[i] -= n
+	}
+	return diff
+}
+
+// addTiles counts rack tiles into the pool
+func (p pool) addTiles(dist *game.LetterDistribution, tiles []game.Tile) {
+	for _, tile := range tiles {
+		p[p.index(dist, tile)]++
+	}
+}
+
+// removeTiles takes placed tiles out of the pool, a designated blank
+// counting as a blank
+func (p pool) removeTiles(dist *game.LetterDistribution, placed []game.PlacedTile) {
+	for _, pt := range placed {
+		p[p.index(dist, pt.Tile)]--
+	}
+}
+
+func (p pool) index(dist *game.LetterDistribution, tile game.Tile) int {
+	if tile.IsBlank {
+		return len(p) - 1
+	}
+	i, _ := dist.Index(tile.Letter)
+	return i
+}
+
+// tiles lists the pool as rack tiles, in distribution order with blanks last
+func (p pool) tiles(dist *game.LetterDistribution) []game.Tile {
+	var tiles []game.Tile
+	for i, n := range p {
+		letter := game.BlankLetter
+		if i < len(dist.Letters) {
+			letter = dist.Letters[i]
+		}
+		for ; n > 0; n-- {
+			tiles = append(tiles, dist.Tile(letter))
+		}
+	}
+	return tiles
+}
+
+// counts returns the pool as a letter count map, with '?' for blanks
+func (p pool) counts(dist *game.LetterDistribution) map[rune]int {
+	counts := make(map[rune]int)
+	for i, n := range p {
+		if n == 0 {
+			continue
+		}
+		if i < len(dist.Letters) {
+			counts[dist.Letters[i]] = n
+		} else {
+			counts[game.BlankLetter] = n
+		}
+	}
+	return counts
+}
+
+// draws calls fn with every distinct set of n tiles that can be drawn from
+// the pool, and the chance of drawing it
+func (p pool) draws(n int, fn func(drawn pool, prob float64)) {
+	total := choose(p.len(), n)
+	drawn := make(pool, len(p))
+
+	var walk func(i, left int, ways float64)
+	walk = func(i, left int, ways float64) {
+		if left == 0 {
+			fn(drawn.clone(), ways/total)
+			return
+		}
+		if i == len(p) {
+			return
+		}
+		for k := min(p[i], left); k >= 0; k-- {
+			drawn[i] = k
+			walk(i+1, left-k, ways*choose(p[i], k))
+		}
+		drawn[i] = 0
+	}
+	walk(0, n, 1)
+}
+
+// choose returns the binomial coefficient n choose k
+func choose(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	c := 1.0
+	for i := 0; i < k; i++ {
+		c = c * float64(n-i) / float64(i+1)
+	}
+	return c
+}
@@ -0,0 +1,230 @@
+package preendgame
+
+import (
+	"errors"
+	"sort"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/endgame"
+	"tiletactics/backend/internal/evaluator"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+)
+
+const (
+	// DefaultCandidates is how many static candidates are analyzed, besides
+	// passing, when Config leaves it unset
+	DefaultCandidates = 10
+
+	// MaxBag is the most tiles the bag may hold for a pre-endgame
+	MaxBag = 6
+
+	rackSize = 7
+)
+
+// ErrNotPreEndgame is returned when the bag does not hold 1 to MaxBag tiles
+var ErrNotPreEndgame = errors.New("bag must hold 1 to 6 tiles")
+
+// Position is the state a move is chosen in, as the player to move sees it
+type Position struct {
+	Board  *board.Board
+	Rack   []game.Tile
+	Unseen map[rune]int // Bag plus the opponent's rack, with '?' for blanks
+	Spread int          // Our score minus the opponent's
+}
+
+// Config controls how much work an analysis does
+type Config struct {
+	Candidates int // Best static moves to analyze, besides passing
+	MaxDepth   int // Turns each endgame is searched; zero searches to the end
+}
+
+// Result is the outcome of one candidate over every possible draw
+type Result struct {
+	Move       game.Move
+	Outcomes   int     // Distinct draws considered
+	MeanSpread float64 // Expected change in spread to the end of the game
+	WinPercent float64 // Chance of finishing ahead on Position.Spread, with ties counting half
+}
+
+// Solver analyzes positions with a few tiles left in the bag. Exchanges are
+// not candidates, as the bag is too small to allow them.
+type Solver struct {
+	lexicon *gaddag.Compact
+	dist    *game.LetterDistribution
+	weights evaluator.Weights
+	leaves  *evaluator.LeaveTable
+}
+
+// New creates a solver that picks candidates with the default evaluator
+func New(lexicon gaddag.Lexicon, dist *game.LetterDistribution) *Solver {
+	return &Solver{
+		lexicon: lexicon.Compact(),
+		dist:    dist,
+		weights: evaluator.DefaultWeights,
+	}
+}
+
+// SetLeaves picks candidates and the opponent's static replies with a
+// trained leave table
+func (s *Solver) SetLeaves(leaves *evaluator.LeaveTable) {
+	s.leaves = leaves
+}
+
+// Solve plays each candidate against every draw the unseen tiles allow.
+// When our draw empties the bag, the opponent's rack is known and the
+// endgame is solved exactly. Otherwise every opponent rack is tried: the
+// opponent makes their best static reply and, once their draw empties the
+// bag, the endgame is solved from our turn; if tiles are still left after
+// that, the scores so far are taken as the outcome. Results are sorted by
+// win percent, then mean spread, best first.
+func (s *Solver) Solve(pos Position, cfg Config) ([]Result, error) {
+	if cfg.Candidates <= 0 {
+		cfg.Candidates = DefaultCandidates
+	}
+
+	unseen := s.poolOf(pos.Unseen)
+	bagLen := unseen.len() - rackSize
+	if bagLen < 1 || bagLen > MaxBag {
+		return nil, ErrNotPreEndgame
+	}
+
+	endgames := endgame.New(s.lexicon, s.dist)
+	endgames.SetMaxDepth(cfg.MaxDepth)
+
+	candidates := s.bestMoves(pos.Board, pos.Rack, pos.Unseen, cfg.Candidates)
+	if !hasPass(candidates) {
+		candidates = append(candidates, game.Move{Kind: game.MovePass})
+	}
+
+	results := make([]Result, len(candidates))
+	for i, move := range candidates {
+		a := &analysis{solver: s, endgames: endgames, spread: pos.Spread}
+		a.candidate(pos, move, unseen, bagLen)
+		results[i] = a.result(move)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].WinPercent != results[j].WinPercent {
+			return results[i].WinPercent > results[j].WinPercent
+		}
+		return results[i].MeanSpread > results[j].MeanSpread
+	})
+
+	return results, nil
+}
+
+// analysis totals the outcomes of one candidate
+type analysis struct {
+	solver   *Solver
+	endgames *endgame.Solver
+	spread   int
+	outcomes int
+	total    float64 // Probability-weighted spread change
+	wins     float64
+}
+
+// candidate plays move and walks every draw that can follow it
+func (a *analysis) candidate(pos Position, move game.Move, unseen pool, bagLen int) {
+	s := a.solver
+	b := pos.Board.Clone()
+	b.Place(move)
+	leave := s.poolOf(nil)
+	leave.addTiles(s.dist, pos.Rack)
+	leave.removeTiles(s.dist, move.TilesPlaced)
+
+	n := min(len(move.TilesPlaced), bagLen)
+	unseen.draws(n, func(drawn pool, p float64) {
+		ours := leave.plus(drawn)
+		rest := unseen.minus(drawn)
+
+		if bagLen == n {
+			// The bag is empty, so the rest is the opponent's rack
+			sol := a.endgames.Solve(b, rest.tiles(s.dist), ours.tiles(s.dist))
+			a.add(move.Score-sol.Spread, p)
+			return
+		}
+
+		rest.draws(rackSize, func(opponent pool, q float64) {
+			a.reply(b, move.Score, ours, opponent, rest.minus(opponent), p*q)
+		})
+	})
+}
+
+// reply has the opponent make their best static move from a known rack
+// and, if their draw empties the bag, solves the endgame that follows
+func (a *analysis) reply(b *board.Board, spread int, ours, opponent, bag pool, p float64) {
+	s := a.solver
+	rack := opponent.tiles(s.dist)
+	move := s.bestMove(b, rack, ours.plus(bag).counts(s.dist))
+	spread -= move.Score
+
+	b.Place(move)
+	defer b.Unplace(move)
+
+	left := opponent.clone()
+	left.removeTiles(s.dist, move.TilesPlaced)
+
+	n := len(move.TilesPlaced)
+	if n < bag.len() {
+		// Still tiles in the bag after their draw; stop at the scores so far
+		a.add(spread, p)
+		return
+	}
+	bag.draws(bag.len(), func(drawn pool, q float64) {
+		sol := a.endgames.Solve(b, ours.tiles(s.dist), left.plus(drawn).tiles(s.dist))
+		a.add(spread+sol.Spread, p*q)
+	})
+}
+
+// add records one outcome with its probability
+func (a *analysis) add(spread int, p float64) {
+	a.outcomes++
+	a.total += p * float64(spread)
+	switch final := a.spread + spread; {
+	case final > 0:
+		a.wins += p
+	case final == 0:
+		a.wins += p / 2
+	}
+}
+
+func (a *analysis) result(move game.Move) Result {
+	return Result{
+		Move:       move,
+		Outcomes:   a.outcomes,
+		MeanSpread: a.total,
+		WinPercent: 100 * a.wins,
+	}
+}
+
+// bestMoves returns the k placements or pass the evaluator rates highest
+func (s *Solver) bestMoves(b *board.Board, rack []game.Tile, unseen map[rune]int, k int) []game.Move {
+	eval := evaluator.NewWithDistribution(unseen, s.weights, s.dist)
+	if s.leaves != nil {
+		eval.SetLeaves(s.leaves)
+	}
+
+	// The bag is too small to exchange from, so passing is the only other move
+	gen := generator.NewWithDistribution(s.lexicon, b, s.dist)
+	ranked := eval.BestMoves(gen, rack, 0, k)
+	moves := make([]game.Move, len(ranked))
+	for i, r := range ranked {
+		moves[i] = r.Move
+	}
+	return moves
+}
+
+// bestMove picks the move the evaluator rates highest
+func (s *Solver) bestMove(b *board.Board, rack []game.Tile, unseen map[rune]int) game.Move {
+	return s.bestMoves(b, rack, unseen, 1)[0]
+}
+
+func hasPass(moves []game.Move) bool {
+	for _, move := range moves {
+		if move.Kind == game.MovePass {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package preendgame
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+)
+
+func newTestSolver() *Solver {
+	g := gaddag.New()
+	for _, word := range []string{
+		"CAT", "CATS", "SCAT", "ACT", "ACTS", "AT", "AS", "TA", "TAS", "SAT",
+		"TEA", "TEAS", "EAT", "EATS", "SEAT", "ATE", "ETA", "ETAS", "EAST",
+	} {
+		g.Add(word)
+	}
+	return New(g, game.EnglishDistribution())
+}
+
+func testPosition(unseen map[rune]int) Position {
+	b := board.New()
+	for i, letter := range "CAT" {
+		b.SetTile(7, 7+i, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+	}
+
+	dist := game.EnglishDistribution()
+	var rack []game.Tile
+	for _, letter := range "SEAQ" {
+		rack = append(rack, dist.Tile(letter))
+	}
+
+	return Position{Board: b, Rack: rack, Unseen: unseen, Spread: -3}
+}
+
+func TestSolve(t *testing.T) {
+	pos := testPosition(map[rune]int{'A': 2, 'E': 2, 'S': 1, 'T': 1, 'Z': 1, 'X': 1})
+	results, err := newTestSolver().Solve(pos, Config{Candidates: 3})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+
+	passed := false
+	for i, r := range results {
+		if r.Move.Kind == game.MovePass {
+			passed = true
+		}
+		if r.Move.Kind == game.MoveExchange {
+			t.Errorf("result %d is an exchange with one tile in the bag", i)
+		}
+		if r.Outcomes == 0 {
+			t.Errorf("result %d considered no draws", i)
+		}
+		if r.WinPercent < 0 || r.WinPercent > 100 {
+			t.Errorf("result %d win percent %v out of range", i, r.WinPercent)
+		}
+		if i > 0 && r.WinPercent > results[i-1].WinPercent {
+			t.Errorf("results not sorted by win percent at %d", i)
+		}
+	}
+	if !passed {
+		t.Error("passing was not analyzed")
+	}
+
+	if pos.Board.GetTile(7, 10) != nil || pos.Board.GetTile(6, 7) != nil || pos.Board.GetTile(8, 8) != nil {
+		t.Error("analysis changed the position's board")
+	}
+}
+
+func TestSolveNeedsPreEndgame(t *testing.T) {
+	solver := newTestSolver()
+	for _, unseen := range []map[rune]int{
+		{'A': 4, 'E': 3},                 // Empty bag
+		{'A': 6, 'E': 4, 'S': 2, 'T': 2}, // Seven in the bag
+	} {
+		if _, err := solver.Solve(testPosition(unseen), Config{}); !errors.Is(err, ErrNotPreEndgame) {
+			t.Errorf("%d unseen tiles: got error %v, want ErrNotPreEndgame", len(unseen), err)
+		}
+	}
+}
+
+func TestDraws(t *testing.T) {
+	solver := newTestSolver()
+	p := solver.poolOf(map[rune]int{'A': 3, 'B': 1, '?': 2})
+
+	total, count := 0.0, 0
+	p.draws(3, func(drawn pool, prob float64) {
+		if drawn.len() != 3 {
+			t.Errorf("drew %d tiles, want 3", drawn.len())
+		}
+		total += prob
+		count++
+	})
+
+	// AAA, AAB, AA?, AB?, A?? and B??
+	if count != 6 {
+		t.Errorf("got %d distinct draws, want 6", count)
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("draw chances sum to %v, want 1", total)
+	}
+}
@@ -7,79 +7,42 @@ import (
 
 type Scorer struct {
 	board *board.Board
+	dist  *game.LetterDistribution
 }
 
+// New creates a scorer that trusts the value carried on each tile
 func New(b *board.Board) *Scorer {
 	return &Scorer{board: b}
 }
 
+// NewWithDistribution creates a scorer that values every non-blank tile,
+// placed or already on the board, from the given distribution
+func NewWithDistribution(b *board.Board, dist *game.LetterDistribution) *Scorer {
+	return &Scorer{board: b, dist: dist}
+}
+
+// BingoBonus is awarded for playing all seven tiles in one move
+const BingoBonus = 50
+
 // ScoreMove calculates the total score for a move
 func (s *Scorer) ScoreMove(move game.Move) int {
-	score := 0
-	wordMultiplier := 1
-
 	// Check if board is empty
 	boardEmpty := s.board.IsCompletelyEmpty()
 
-	// Track if this move connects to existing tiles
-	connectsToExisting := false
-	usesExistingInMainWord := false
-
-	// Calculate score for each letter in the word
-	for i := range move.Word {
-		letterScore := 0
-		letterMultiplier := 1
-
-		// Determine position of this letter
-		row, col := s.getLetterPosition(move.Position, i, move.Direction)
-
-		// Check if this position already has a tile
-		existingTile := s.board.GetTile(row, col)
-		if existingTile != nil {
-			// Using existing tile - just add base value
-			letterScore = existingTile.Value
-			connectsToExisting = true
-			usesExistingInMainWord = true
-		} else {
-			// Placing new tile - check for multipliers
-			multiplier := s.board.GetMultiplier(row, col)
-
-			// Find the tile being placed at this position
-			for _, placed := range move.TilesPlaced {
-				if placed.Position.Row == row && placed.Position.Col == col {
-					letterScore = placed.Tile.Value
-					break
-				}
-			}
-
-			// Apply multiplier only for newly placed tiles
-			switch multiplier.Type {
-			case board.DoubleLetter:
-				letterMultiplier = 2
-			case board.TripleLetter:
-				letterMultiplier = 3
-			case board.DoubleWord:
-				wordMultiplier *= 2
-			case board.TripleWord:
-				wordMultiplier *= 3
-			default:
-				// No multiplier
-			}
-		}
-
-		score += letterScore * letterMultiplier
-	}
-
-	// Apply word multiplier
-	score *= wordMultiplier
+	mainWord, usesExistingInMainWord := s.scoreMainWord(move)
+	score := mainWord.Score
 
 	// Add bonus for using all 7 tiles (bingo)
-	if len(move.TilesPlaced) == 7 {
-		score += 50
-	}
+	score += s.bingoBonus(move)
+
+	// Track if this move connects to existing tiles
+	connectsToExisting := usesExistingInMainWord
 
 	// Add scores from perpendicular words formed
-	crossWordScore := s.calculateCrossWordScores(move)
+	crossWordScore := 0
+	for _, crossWord := range s.crossWordScores(move) {
+		crossWordScore += crossWord.Score
+	}
 	if crossWordScore > 0 {
 		connectsToExisting = true
 	}
@@ -88,11 +51,12 @@ func (s *Scorer) ScoreMove(move game.Move) int {
 	// Special case for first move - board is empty
 	if boardEmpty {
 		// First move doesn't need to connect to existing tiles
-		// Just ensure it covers center square (7,7)
+		// Just ensure it covers the layout's center square
+		center := s.board.Center()
 		centerCovered := false
-		for i := range move.Word {
+		for i := range []rune(move.Word) {
 			row, col := s.getLetterPosition(move.Position, i, move.Direction)
-			if row == 7 && col == 7 {
+			if row == center.Row && col == center.Col {
 				centerCovered = true
 				break
 			}
@@ -108,7 +72,7 @@ func (s *Scorer) ScoreMove(move game.Move) int {
 	// 2. It forms a cross word (crossWordScore > 0), OR
 	// 3. At least one placed tile is adjacent to an existing tile
 
-	if !connectsToExisting && !usesExistingInMainWord {
+	if !connectsToExisting {
 		// Check if any placed tile is adjacent to existing tiles
 		for _, placed := range move.TilesPlaced {
 			if s.isAdjacentToExistingTile(placed.Position) {
@@ -125,6 +89,104 @@ func (s *Scorer) ScoreMove(move game.Move) int {
 	return score
 }
 
+// Breakdown scores each word a move forms, main word first followed by any
+// cross words, along with the bingo bonus. Unlike ScoreMove it does not
+// check that the move is connected to the board.
+func (s *Scorer) Breakdown(move game.Move) game.ScoreBreakdown {
+	mainWord, _ := s.scoreMainWord(move)
+	return game.ScoreBreakdown{
+		Words: append([]game.WordScore{mainWord}, s.crossWordScores(move)...),
+		Bingo: s.bingoBonus(move),
+	}
+}
+
+// scoreMainWord scores the word along the move's direction and reports
+// whether it runs through tiles already on the board
+func (s *Scorer) scoreMainWord(move game.Move) (game.WordScore, bool) {
+	score := 0
+	wordMultiplier := 1
+	usesExisting := false
+	var premiums []game.Premium
+
+	// Calculate score for each letter in the word
+	for i := range []rune(move.Word) {
+		letterScore := 0
+		letterMultiplier := 1
+
+		// Determine position of this letter
+		row, col := s.getLetterPosition(move.Position, i, move.Direction)
+
+		// Check if this position already has a tile
+		existingTile := s.board.GetTile(row, col)
+		if existingTile != nil {
+			// Using existing tile - just add base value
+			letterScore = s.tileValue(existingTile)
+			usesExisting = true
+		} else {
+			// Placing new tile - check for multipliers
+			multiplier := s.board.GetMultiplier(row, col)
+
+			// Find the tile being placed at this position
+			for _, placed := range move.TilesPlaced {
+				if placed.Position.Row == row && placed.Position.Col == col {
+					letterScore = s.tileValue(&placed.Tile)
+					break
+				}
+			}
+
+			if premium, ok := premiumAt(multiplier, row, col); ok {
+				premiums = append(premiums, premium)
+			}
+
+			// Apply multiplier only for newly placed tiles
+			letterMultiplier = multiplier.LetterMultiplier()
+			wordMultiplier *= multiplier.WordMultiplier()
+		}
+
+		score += letterScore * letterMultiplier
+	}
+
+	// Apply word multiplier
+	return game.WordScore{
+		Word:           move.Word,
+		Premiums:       premiums,
+		WordMultiplier: wordMultiplier,
+		Score:          score * wordMultiplier,
+	}, usesExisting
+}
+
+// tileValue returns what a tile scores before multipliers
+func (s *Scorer) tileValue(tile *game.Tile) int {
+	if tile.IsBlank {
+		return 0
+	}
+	if s.dist != nil {
+		return s.dist.Value(tile.Letter)
+	}
+	return tile.Value
+}
+
+// premiumAt describes a multiplier for the score breakdown, returning false
+// for an ordinary square
+func premiumAt(multiplier board.Multiplier, row, col int) (game.Premium, bool) {
+	if multiplier.Type == board.None {
+		return game.Premium{}, false
+	}
+	return game.Premium{
+		Position:   game.Position{Row: row, Col: col},
+		Multiplier: multiplier.Value,
+		IsWord:     multiplier.IsWord(),
+	}, true
+}
+
+// bingoBonus returns the bonus for a move that uses all seven tiles
+func (s *Scorer) bingoBonus(move game.Move) int {
+	if len(move.TilesPlaced) == 7 {
+		return BingoBonus
+	}
+	return 0
+}
+
 // isAdjacentToExistingTile checks if a position is adjacent to any existing tile
 func (s *Scorer) isAdjacentToExistingTile(pos game.Position) bool {
 	// Check all four directions
@@ -136,11 +198,8 @@ func (s *Scorer) isAdjacentToExistingTile(pos game.Position) bool {
 	}
 
 	for _, adjPos := range adjacentPositions {
-		if adjPos.Row >= 0 && adjPos.Row < game.BoardSize &&
-			adjPos.Col >= 0 && adjPos.Col < game.BoardSize {
-			if s.board.GetTile(adjPos.Row, adjPos.Col) != nil {
-				return true
-			}
+		if s.board.GetTile(adjPos.Row, adjPos.Col) != nil {
+			return true
 		}
 	}
 
@@ -155,20 +214,22 @@ func (s *Scorer) getLetterPosition(start game.Position, offset int, dir game.Dir
 	return start.Row + offset, start.Col
 }
 
-// calculateCrossWordScores calculates scores from perpendicular words formed
-func (s *Scorer) calculateCrossWordScores(move game.Move) int {
-	totalCrossScore := 0
+// crossWordScores scores the perpendicular words formed by each placed tile
+func (s *Scorer) crossWordScores(move game.Move) []game.WordScore {
+	var crossWords []game.WordScore
 
 	for _, placed := range move.TilesPlaced {
-		crossScore := s.getCrossWordScore(placed.Position, placed.Tile, move.Direction)
-		totalCrossScore += crossScore
+		if crossWord, ok := s.getCrossWordScore(placed.Position, placed.Tile, move.Direction); ok {
+			crossWords = append(crossWords, crossWord)
+		}
 	}
 
-	return totalCrossScore
+	return crossWords
 }
 
-// getCrossWordScore calculates the score of a perpendicular word formed by placing a tile
-func (s *Scorer) getCrossWordScore(pos game.Position, tile game.Tile, mainDir game.Direction) int {
+// getCrossWordScore scores the perpendicular word formed by placing a tile,
+// returning false if the tile forms no perpendicular word
+func (s *Scorer) getCrossWordScore(pos game.Position, tile game.Tile, mainDir game.Direction) (game.WordScore, bool) {
 	// Perpendicular direction
 	crossDir := game.Vertical
 	if mainDir == game.Vertical {
@@ -179,46 +240,33 @@ func (s *Scorer) getCrossWordScore(pos game.Position, tile game.Tile, mainDir ga
 	startPos := s.findWordStart(pos, crossDir)
 	endPos := s.findWordEnd(pos, crossDir)
 
-	// If no perpendicular word formed, return 0
+	// If no perpendicular word formed, there is nothing to score
 	if startPos.Row == endPos.Row && startPos.Col == endPos.Col {
-		return 0
+		return game.WordScore{}, false
 	}
 
 	// Calculate score of perpendicular word
+	word := ""
 	score := 0
 	wordMultiplier := 1
 
 	// Check if we're using a multiplier
 	multiplier := s.board.GetMultiplier(pos.Row, pos.Col)
-	switch multiplier.Type {
-	case board.DoubleWord:
-		wordMultiplier = 2
-	case board.TripleWord:
-		wordMultiplier = 3
-	default:
-		// No word multiplier
-	}
+	wordMultiplier = multiplier.WordMultiplier()
 
 	// Score each letter in the cross word
 	currentPos := startPos
 	for {
 		if currentPos.Row == pos.Row && currentPos.Col == pos.Col {
 			// This is the newly placed tile
-			letterMultiplier := 1
-			switch multiplier.Type {
-			case board.DoubleLetter:
-				letterMultiplier = 2
-			case board.TripleLetter:
-				letterMultiplier = 3
-			default:
-				// No letter multiplier
-			}
-			score += tile.Value * letterMultiplier
+			word += string(tile.Letter)
+			score += s.tileValue(&tile) * multiplier.LetterMultiplier()
 		} else {
 			// Existing tile
 			existingTile := s.board.GetTile(currentPos.Row, currentPos.Col)
 			if existingTile != nil {
-				score += existingTile.Value
+				word += string(existingTile.Letter)
+				score += s.tileValue(existingTile)
 			}
 		}
 
@@ -234,7 +282,15 @@ func (s *Scorer) getCrossWordScore(pos game.Position, tile game.Tile, mainDir ga
 		}
 	}
 
-	return score * wordMultiplier
+	crossWord := game.WordScore{
+		Word:           word,
+		WordMultiplier: wordMultiplier,
+		Score:          score * wordMultiplier,
+	}
+	if premium, ok := premiumAt(multiplier, pos.Row, pos.Col); ok {
+		crossWord.Premiums = []game.Premium{premium}
+	}
+	return crossWord, true
 }
 
 // findWordStart finds the start of a word in the given direction
@@ -278,7 +334,7 @@ func (s *Scorer) findWordEnd(pos game.Position, dir game.Direction) game.Positio
 		}
 
 		// Check bounds
-		if next.Row >= game.BoardSize || next.Col >= game.BoardSize {
+		if next.Row >= s.board.Size() || next.Col >= s.board.Size() {
 			break
 		}
 
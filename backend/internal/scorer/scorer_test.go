@@ -0,0 +1,100 @@
+package scorer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/game"
+)
+
+func TestBreakdownOpeningMove(t *testing.T) {
+	b := board.New()
+	move := game.Move{
+		Word:      "CAT",
+		Position:  game.Position{Row: 7, Col: 7},
+		Direction: game.Horizontal,
+		TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 7, Col: 7}, Tile: game.Tile{Letter: 'C', Value: 3}},
+			{Position: game.Position{Row: 7, Col: 8}, Tile: game.Tile{Letter: 'A', Value: 1}},
+			{Position: game.Position{Row: 7, Col: 9}, Tile: game.Tile{Letter: 'T', Value: 1}},
+		},
+	}
+
+	got := New(b).Breakdown(move)
+	want := game.ScoreBreakdown{
+		Words: []game.WordScore{{
+			Word:           "CAT",
+			Premiums:       []game.Premium{{Position: game.Position{Row: 7, Col: 7}, Multiplier: 2, IsWord: true}},
+			WordMultiplier: 2,
+			Score:          10,
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Breakdown() = %+v, want %+v", got, want)
+	}
+	if score := New(b).ScoreMove(move); score != 10 {
+		t.Errorf("ScoreMove() = %d, want 10", score)
+	}
+}
+
+func TestBreakdownCrossWordPremium(t *testing.T) {
+	b := board.New()
+	b.SetTile(7, 7, &game.Tile{Letter: 'C', Value: 3})
+	b.SetTile(7, 8, &game.Tile{Letter: 'A', Value: 1})
+	b.SetTile(7, 9, &game.Tile{Letter: 'T', Value: 1})
+
+	// SH across under AT: S on the double letter at (8,8) also forms AS down
+	move := game.Move{
+		Word:      "SH",
+		Position:  game.Position{Row: 8, Col: 8},
+		Direction: game.Horizontal,
+		TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 8, Col: 8}, Tile: game.Tile{Letter: 'S', Value: 1}},
+			{Position: game.Position{Row: 8, Col: 9}, Tile: game.Tile{Letter: 'H', Value: 4}},
+		},
+	}
+
+	got := New(b).Breakdown(move)
+	if len(got.Words) != 3 {
+		t.Fatalf("Breakdown() returned %d words, want 3: %+v", len(got.Words), got.Words)
+	}
+
+	dl := game.Premium{Position: game.Position{Row: 8, Col: 8}, Multiplier: 2}
+	if w := got.Words[0]; w.Word != "SH" || w.Score != 6 || !reflect.DeepEqual(w.Premiums, []game.Premium{dl}) {
+		t.Errorf("main word = %+v, want SH scoring 6 with the DL", w)
+	}
+	if w := got.Words[1]; w.Word != "AS" || w.Score != 3 || !reflect.DeepEqual(w.Premiums, []game.Premium{dl}) {
+		t.Errorf("first cross word = %+v, want AS scoring 3 with the DL", w)
+	}
+	if w := got.Words[2]; w.Word != "TH" || w.Score != 5 || len(w.Premiums) != 0 {
+		t.Errorf("second cross word = %+v, want TH scoring 5", w)
+	}
+	if dl.Label() != "DL" {
+		t.Errorf("Label() = %q, want DL", dl.Label())
+	}
+}
+
+func TestScoreMoveWithDistribution(t *testing.T) {
+	dist, err := game.ParseDistribution("house", strings.NewReader("C 2 5\nA 9 2\nT 6 2\n? 2 0\n"))
+	if err != nil {
+		t.Fatalf("ParseDistribution() error = %v", err)
+	}
+
+	// Tile values on the move are ignored in favour of the distribution
+	move := game.Move{
+		Word:      "CAT",
+		Position:  game.Position{Row: 7, Col: 7},
+		Direction: game.Horizontal,
+		TilesPlaced: []game.PlacedTile{
+			{Position: game.Position{Row: 7, Col: 7}, Tile: game.Tile{Letter: 'C', Value: 3}},
+			{Position: game.Position{Row: 7, Col: 8}, Tile: game.Tile{Letter: 'A', Value: 1, IsBlank: true}},
+			{Position: game.Position{Row: 7, Col: 9}, Tile: game.Tile{Letter: 'T', Value: 1}},
+		},
+	}
+
+	// (5 + 0 + 2) doubled by the centre square
+	if score := NewWithDistribution(board.New(), dist).ScoreMove(move); score != 14 {
+		t.Errorf("ScoreMove() = %d, want 14", score)
+	}
+}
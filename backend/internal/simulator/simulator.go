@@ -0,0 +1,306 @@
+package simulator
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/evaluator"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/generator"
+	"tiletactics/backend/internal/inference"
+	"time"
+)
+
+const (
+	// DefaultCandidates is how many static candidates are simulated when
+	// Config leaves it unset
+	DefaultCandidates = 10
+
+	// DefaultIterations is how many times each candidate is played out when
+	// Config leaves it unset
+	DefaultIterations = 100
+
+	rackSize = 7
+
+	// spreadDeviation is how far, in points, the spread typically swings
+	// over the rest of a game, per square root of the tiles still to be
+	// played: about 80 points from the opening
+	spreadDeviation = 8.0
+)
+
+// Position is the state a move is chosen in, as the player to move sees it
+type Position struct {
+	Board  *board.Board
+	Rack   []game.Tile
+	Unseen map[rune]int // Bag plus the opponent's rack, with '?' for blanks
+	Spread int          // Our score minus the opponent's
+
+	// OpponentLeaves weights what the opponent may have kept from their last
+	// move, as from inference.Infer. Their rack is dealt from one of these
+	// leaves topped up from the bag; when nil it is dealt from the bag alone.
+	OpponentLeaves []inference.Leave
+}
+
+// Config controls how much work a simulation does
+type Config struct {
+	Candidates int           // Best static moves to simulate
+	Iterations int           // Playouts per candidate
+	TimeLimit  time.Duration // Stop after the round running when this passes; zero means no limit
+	Seed       int64         // Playout i draws with Seed+i, the same for every candidate
+}
+
+// Result is the outcome of simulating one candidate
+type Result struct {
+	Move       game.Move
+	Static     float64 // The evaluator's rating that made it a candidate
+	Iterations int
+	MeanSpread float64 // Average of our score, less the reply, plus our follow-up
+	WinPercent float64 // Estimated chance of finishing ahead on Position.Spread, with ties counting half; see Simulate
+}
+
+// Simulator plays candidate moves forward against plausible opponent racks
+type Simulator struct {
+	lexicon *gaddag.Compact
+	dist    *game.LetterDistribution
+	weights evaluator.Weights
+	leaves  *evaluator.LeaveTable
+}
+
+// New creates a simulator whose players pick moves with the default evaluator
+func New(lexicon gaddag.Lexicon, dist *game.LetterDistribution) *Simulator {
+	return &Simulator{
+		lexicon: lexicon.Compact(),
+		dist:    dist,
+		weights: evaluator.DefaultWeights,
+	}
+}
+
+// SetLeaves gives both players' evaluators a leave table, so candidates and
+// the moves played in playouts are picked with trained leave values
+func (s *Simulator) SetLeaves(leaves *evaluator.LeaveTable) {
+	s.leaves = leaves
+}
+
+// Simulate picks the best static candidates for the position and plays each
+// out: a random opponent rack is drawn from the unseen tiles, the opponent
+// makes their best reply and we make our best follow-up. Results are sorted
+// by mean spread, best first. The same seed and iteration count always give
+// the same results.
+//
+// Playouts stop short of the end of the game, so win percent is an
+// estimate: the spread still to come is taken to be normal around zero,
+// narrowing as the tiles run out, and each playout counts for its chance of
+// finishing ahead. A playout in which someone goes out counts exactly.
+func (s *Simulator) Simulate(pos Position, cfg Config) []Result {
+	if cfg.Candidates <= 0 {
+		cfg.Candidates = DefaultCandidates
+	}
+	if cfg.Iterations <= 0 {
+		cfg.Iterations = DefaultIterations
+	}
+	start := time.Now()
+
+	unseen, _ := game.NewRackFromCounts(s.dist, pos.Unseen)
+	bagLen := max(unseen.Len()-rackSize, 0)
+	gen := generator.NewWithDistribution(s.lexicon, pos.Board, s.dist)
+	candidates := s.bestMoves(gen, pos.Rack, pos.Unseen, bagLen, cfg.Candidates)
+
+	results := make([]Result, len(candidates))
+	totals := make([]int, len(candidates))
+	wins := make([]float64, len(candidates))
+	for i, c := range candidates {
+		results[i] = Result{Move: c.move, Static: c.value}
+	}
+
+	for iter := 0; iter < cfg.Iterations; iter++ {
+		for i, c := range candidates {
+			rng := rand.New(rand.NewSource(cfg.Seed + int64(iter)))
+			spread, tilesLeft := s.playout(pos, c.move, rng)
+
+			totals[i] += spread
+			wins[i] += winChance(pos.Spread+spread, tilesLeft)
+			results[i].Iterations++
+		}
+
+		if cfg.TimeLimit > 0 && time.Since(start) >= cfg.TimeLimit {
+			break
+		}
+	}
+
+	for i := range results {
+		if n := float64(results[i].Iterations); n > 0 {
+			results[i].MeanSpread = float64(totals[i]) / n
+			results[i].WinPercent = 100 * wins[i] / n
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].MeanSpread > results[j].MeanSpread
+	})
+
+	return results
+}
+
+// playout plays one candidate forward and returns the change in spread,
+// with how many tiles are left to play, none if someone went out
+func (s *Simulator) playout(pos Position, move game.Move, rng *rand.Rand) (int, int) {
+	// Deal the opponent a rack from the unseen tiles; the rest is the bag
+	unseen, _ := game.NewRackFromCounts(s.dist, pos.Unseen)
+	pool := unseen.Tiles()
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	if len(pos.OpponentLeaves) > 0 {
+		pool = putFirst(pool, pickLeave(pos.OpponentLeaves, rng))
+	}
+	n := min(rackSize, len(pool))
+	opponent, bag := pool[:n], pool[n:]
+
+	// One generator follows the board through the playout
+	b := pos.Board.Clone()
+	gen := generator.NewWithDistribution(s.lexicon, b, s.dist)
+	spread := move.Score
+
+	// Our rack after the candidate and drawing to it
+	ours := s.leave(pos.Rack, move)
+	used := move.TilesUsed()
+	drawn := min(len(used), len(bag))
+	ours, bag = append(ours, bag[:drawn]...), bag[drawn:]
+	if move.Kind == game.MoveExchange {
+		bag = returnTiles(bag, move.Exchanged, rng)
+	}
+	b.Place(move)
+	gen.UpdateCrossChecks(move)
+
+	if len(ours) == 0 {
+		// We went out
+		return spread + 2*s.rackValue(opponent), 0
+	}
+
+	reply := s.bestMove(gen, opponent, s.counts(bag, ours), len(bag))
+	spread -= reply.Score
+	opponent = s.leave(opponent, reply)
+	drawn = min(len(reply.TilesUsed()), len(bag))
+	opponent, bag = append(opponent, bag[:drawn]...), bag[drawn:]
+	if reply.Kind == game.MoveExchange {
+		bag = returnTiles(bag, reply.Exchanged, rng)
+	}
+	b.Place(reply)
+	gen.UpdateCrossChecks(reply)
+
+	if len(opponent) == 0 {
+		// They went out
+		return spread - 2*s.rackValue(ours), 0
+	}
+
+	followUp := s.bestMove(gen, ours, s.counts(bag, opponent), len(bag))
+	ours = s.leave(ours, followUp)
+	return spread + followUp.Score, len(bag) + len(opponent) + len(ours)
+}
+
+// returnTiles puts exchanged tiles back in the bag and shuffles it, so the
+// next draw is as likely to take them as any other tile
+func returnTiles(bag, tiles []game.Tile, rng *rand.Rand) []game.Tile {
+	bag = append(bag, tiles...)
+	rng.Shuffle(len(bag), func(i, j int) { bag[i], bag[j] = bag[j], bag[i] })
+	return bag
+}
+
+// winChance estimates the chance of finishing ahead from a spread with
+// tilesLeft still to be played, ties counting half
+func winChance(spread, tilesLeft int) float64 {
+	if tilesLeft == 0 {
+		switch {
+		case spread > 0:
+			return 1
+		case spread < 0:
+			return 0
+		}
+		return 0.5
+	}
+	deviation := spreadDeviation * math.Sqrt(float64(tilesLeft))
+	return 0.5 * (1 + math.Erf(float64(spread)/(deviation*math.Sqrt2)))
+}
+
+// candidate is a move with its static rating
+type candidate struct {
+	move  game.Move
+	value float64
+}
+
+// bestMove picks the move the evaluator rates highest, passing if there is nothing else
+func (s *Simulator) bestMove(gen *generator.Generator, rack []game.Tile, unseen map[rune]int, bagLen int) game.Move {
+	best := s.bestMoves(gen, rack, unseen, bagLen, 1)
+	if len(best) == 0 {
+		return game.Move{Kind: game.MovePass}
+	}
+	return best[0].move
+}
+
+// bestMoves returns the k moves the evaluator rates highest, including
+// exchanges when the bag allows them and passing
+func (s *Simulator) bestMoves(gen *generator.Generator, rack []game.Tile, unseen map[rune]int, bagLen, k int) []candidate {
+	eval := evaluator.NewWithDistribution(unseen, s.weights, s.dist)
+	if s.leaves != nil {
+		eval.SetLeaves(s.leaves)
+	}
+
+	ranked := eval.BestMoves(gen, rack, bagLen, k)
+	best := make([]candidate, len(ranked))
+	for i, r := range ranked {
+		best[i] = candidate{move: r.Move, value: r.Value}
+	}
+	return best
+}
+
+// leave returns the tiles left on the rack after a move
+func (s *Simulator) leave(rack []game.Tile, move game.Move) []game.Tile {
+	r, _ := game.NewRack(s.dist, rack)
+	r.RemoveTiles(move.TilesUsed())
+	return r.Tiles()
+}
+
+// pickLeave chooses one of the leaves at random by weight
+func pickLeave(leaves []inference.Leave, rng *rand.Rand) []game.Tile {
+	r := rng.Float64()
+	for _, leave := range leaves {
+		if r -= leave.Weight; r < 0 {
+			return leave.Tiles
+		}
+	}
+	return leaves[len(leaves)-1].Tiles
+}
+
+// putFirst moves the tiles of leave to the front of pool, keeping the
+// order of the rest. Tiles of the leave missing from pool are skipped.
+func putFirst(pool, leave []game.Tile) []game.Tile {
+	front := 0
+	for _, want := range leave {
+		for i := front; i < len(pool); i++ {
+			if pool[i].IsBlank == want.IsBlank && (want.IsBlank || pool[i].Letter == want.Letter) {
+				tile := pool[i]
+				copy(pool[front+1:i+1], pool[front:i])
+				pool[front] = tile
+				front++
+				break
+			}
+		}
+	}
+	return pool
+}
+
+// counts counts the tiles of every group by letter, with '?' for blanks
+func (s *Simulator) counts(groups ...[]game.Tile) map[rune]int {
+	r, _ := game.NewRack(s.dist, nil)
+	for _, tiles := range groups {
+		for _, tile := range tiles {
+			r.Add(tile)
+		}
+	}
+	return r.Counts()
+}
+
+// rackValue is what the tiles on a rack score, blanks counting nothing
+func (s *Simulator) rackValue(rack []game.Tile) int {
+	r, _ := game.NewRack(s.dist, rack)
+	return r.Value()
+}
@@ -0,0 +1,162 @@
+package simulator
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/inference"
+)
+
+func newTestSimulator() *Simulator {
+	g := gaddag.New()
+	for _, word := range []string{
+		"CAT", "CATS", "SCAT", "ACT", "ACTS", "AT", "AS", "TA", "TAS", "SAT",
+		"TEA", "TEAS", "EAT", "EATS", "SEAT", "ATE", "ETA", "ETAS", "EAST",
+		"RAT", "RATS", "STAR", "ARTS", "TAR", "TARS", "ART", "RATE", "RATES",
+		"EAR", "EARS", "ERA", "ERAS", "ARE", "ARES", "SEA", "SET", "RES",
+	} {
+		g.Add(word)
+	}
+	return New(g, game.EnglishDistribution())
+}
+
+func testPosition() Position {
+	b := board.New()
+	for i, letter := range "CAT" {
+		b.SetTile(7, 7+i, &game.Tile{Letter: letter, Value: game.TileValues[letter]})
+	}
+
+	dist := game.EnglishDistribution()
+	var rack []game.Tile
+	for _, letter := range "SEATRQZ" {
+		rack = append(rack, dist.Tile(letter))
+	}
+
+	return Position{
+		Board:  b,
+		Rack:   rack,
+		Unseen: map[rune]int{'A': 4, 'E': 4, 'R': 3, 'S': 2, 'T': 3, 'X': 1, '?': 1},
+		Spread: -5,
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	sim := newTestSimulator()
+	pos := testPosition()
+	cfg := Config{Candidates: 4, Iterations: 6, Seed: 7}
+
+	results := sim.Simulate(pos, cfg)
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	for i, r := range results {
+		if r.Iterations != 6 {
+			t.Errorf("result %d ran %d iterations, want 6", i, r.Iterations)
+		}
+		if r.WinPercent < 0 || r.WinPercent > 100 {
+			t.Errorf("result %d win percent %v out of range", i, r.WinPercent)
+		}
+		if i > 0 && r.MeanSpread > results[i-1].MeanSpread {
+			t.Errorf("results not sorted by mean spread at %d", i)
+		}
+	}
+
+	if again := sim.Simulate(pos, cfg); !reflect.DeepEqual(again, results) {
+		t.Error("same seed gave different results")
+	}
+	if pos.Board.GetTile(7, 10) != nil || pos.Board.GetTile(6, 7) != nil {
+		t.Error("simulation changed the position's board")
+	}
+}
+
+func TestSimulateTimeLimit(t *testing.T) {
+	results := newTestSimulator().Simulate(testPosition(), Config{Candidates: 2, Iterations: 1000000, TimeLimit: 1})
+	for _, r := range results {
+		if r.Iterations != 1 {
+			t.Errorf("ran %d iterations past the time limit, want 1", r.Iterations)
+		}
+	}
+}
+
+func TestSimulateWithOpponentLeaves(t *testing.T) {
+	sim := newTestSimulator()
+	pos := testPosition()
+	dist := game.EnglishDistribution()
+	pos.OpponentLeaves = []inference.Leave{
+		{Tiles: []game.Tile{dist.Tile('X'), dist.Tile('S')}, Weight: 0.75},
+		{Tiles: []game.Tile{dist.Tile(game.BlankLetter)}, Weight: 0.25},
+	}
+	cfg := Config{Candidates: 3, Iterations: 4, Seed: 2}
+
+	results := sim.Simulate(pos, cfg)
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if again := sim.Simulate(pos, cfg); !reflect.DeepEqual(again, results) {
+		t.Error("same seed gave different results")
+	}
+}
+
+func TestPutFirst(t *testing.T) {
+	dist := game.EnglishDistribution()
+	var pool []game.Tile
+	for _, letter := range "ABSX?E" {
+		pool = append(pool, dist.Tile(letter))
+	}
+	pool = putFirst(pool, []game.Tile{dist.Tile('X'), dist.Tile(game.BlankLetter), dist.Tile('Z')})
+
+	got := ""
+	for _, tile := range pool {
+		got += string(tile.Letter)
+	}
+	if got != "X?ABSE" {
+		t.Errorf("putFirst gave %s, want X?ABSE", got)
+	}
+}
+
+func TestWinChance(t *testing.T) {
+	for _, tc := range []struct {
+		spread, tilesLeft int
+		want              float64
+	}{
+		{10, 0, 1}, {-1, 0, 0}, {0, 0, 0.5}, {0, 50, 0.5},
+	} {
+		if got := winChance(tc.spread, tc.tilesLeft); got != tc.want {
+			t.Errorf("winChance(%d, %d) = %v, want %v", tc.spread, tc.tilesLeft, got, tc.want)
+		}
+	}
+
+	// A lead counts for more as the tiles run out
+	early, late := winChance(30, 80), winChance(30, 10)
+	if early <= 0.5 || late <= early || late >= 1 {
+		t.Errorf("a 30 point lead wins %v with 80 tiles left and %v with 10, want 0.5 < early < late < 1", early, late)
+	}
+	if got := winChance(-30, 80); math.Abs(got+early-1) > 1e-9 {
+		t.Errorf("a 30 point deficit wins %v, want %v", got, 1-early)
+	}
+}
+
+func TestReturnTiles(t *testing.T) {
+	dist := game.EnglishDistribution()
+	var bag []game.Tile
+	for _, letter := range "ABCDEFGHIJ" {
+		bag = append(bag, dist.Tile(letter))
+	}
+	bag = returnTiles(bag, []game.Tile{dist.Tile('Q'), dist.Tile('Z')}, rand.New(rand.NewSource(1)))
+
+	got := ""
+	for _, tile := range bag {
+		got += string(tile.Letter)
+	}
+	if len(got) != 12 || !strings.Contains(got, "Q") || !strings.Contains(got, "Z") {
+		t.Fatalf("bag after returning QZ = %s, want all 12 tiles", got)
+	}
+	if strings.HasSuffix(got, "QZ") {
+		t.Errorf("returned tiles left at the bottom of the bag: %s", got)
+	}
+}
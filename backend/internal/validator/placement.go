@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/game"
+	"tiletactics/backend/internal/scorer"
+)
+
+// ScoredPlacement is a human-entered placement worked out into a move and scored
+type ScoredPlacement struct {
+	Move       game.Move // Main word, start, direction, total score and breakdown
+	PhonyWords []string  // Words formed that are not in the dictionary
+}
+
+// InferMove works out the main word, its start square and its direction from
+// tiles placed on the board. A single tile takes the direction in which it
+// forms the longer word.
+func InferMove(b *board.Board, placed []game.PlacedTile) (game.Move, error) {
+	dir, err := checkPlacement(b, placed)
+	if err != nil {
+		return game.Move{}, err
+	}
+
+	at := tileLookup(b, placed)
+	anchor := placed[0].Position
+
+	if len(placed) == 1 {
+		across := readWord(at, anchor, game.Horizontal)
+		down := readWord(at, anchor, game.Vertical)
+		if len([]rune(down)) > len([]rune(across)) {
+			dir = game.Vertical
+		}
+	}
+
+	start := anchor
+	for at(step(start, dir, -1)) != nil {
+		start = step(start, dir, -1)
+	}
+
+	word := readWord(at, anchor, dir)
+	if len([]rune(word)) < 2 {
+		return game.Move{}, ErrNoWordFormed
+	}
+
+	return game.Move{
+		Kind:        game.MovePlace,
+		Word:        word,
+		Position:    start,
+		Direction:   dir,
+		TilesPlaced: placed,
+	}, nil
+}
+
+// ScorePlacement infers the move made by placing tiles on the board, scores
+// every word it forms and checks each against the dictionary. Tiles are
+// valued from the validator's distribution, whatever value they carry.
+// Placement errors are returned as for Validate; phony words are reported in
+// the result instead so the caller can still show the score breakdown.
+func (v *Validator) ScorePlacement(b *board.Board, placed []game.PlacedTile) (ScoredPlacement, error) {
+	move, err := InferMove(b, placed)
+	if err != nil {
+		return ScoredPlacement{}, err
+	}
+
+	move.Breakdown = scorer.NewWithDistribution(b, v.dist).Breakdown(move)
+
+	formed := make([]string, len(move.Breakdown.Words))
+	for i, word := range move.Breakdown.Words {
+		formed[i] = word.Word
+		move.Score += word.Score
+	}
+	move.Score += move.Breakdown.Bingo
+
+	return ScoredPlacement{
+		Move:       move,
+		PhonyWords: v.phonyWords(formed),
+	}, nil
+}
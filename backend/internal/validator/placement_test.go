@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"tiletactics/backend/internal/game"
+)
+
+func TestInferMove(t *testing.T) {
+	b := boardWithCat()
+
+	tests := []struct {
+		name      string
+		placed    []game.PlacedTile
+		wantWord  string
+		wantStart game.Position
+		wantDir   game.Direction
+	}{
+		{
+			name:      "Single tile extends across",
+			placed:    []game.PlacedTile{place(7, 10, 'S')},
+			wantWord:  "CATS",
+			wantStart: game.Position{Row: 7, Col: 7},
+			wantDir:   game.Horizontal,
+		},
+		{
+			name:      "Single tile forms word down",
+			placed:    []game.PlacedTile{place(8, 8, 'S')},
+			wantWord:  "AS",
+			wantStart: game.Position{Row: 7, Col: 8},
+			wantDir:   game.Vertical,
+		},
+		{
+			name:      "Tiles down through a cross word",
+			placed:    []game.PlacedTile{place(7, 10, 'S'), place(6, 10, 'A')},
+			wantWord:  "AS",
+			wantStart: game.Position{Row: 6, Col: 10},
+			wantDir:   game.Vertical,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			move, err := InferMove(b, tt.placed)
+			if err != nil {
+				t.Fatalf("InferMove() error = %v", err)
+			}
+			if move.Word != tt.wantWord || move.Position != tt.wantStart || move.Direction != tt.wantDir {
+				t.Errorf("InferMove() = %s at %v dir %d, want %s at %v dir %d",
+					move.Word, move.Position, move.Direction, tt.wantWord, tt.wantStart, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestInferMoveRejectsBadPlacement(t *testing.T) {
+	_, err := InferMove(boardWithCat(), []game.PlacedTile{place(0, 0, 'A'), place(1, 1, 'T')})
+	if !errors.Is(err, ErrNotCollinear) {
+		t.Errorf("InferMove() error = %v, want ErrNotCollinear", err)
+	}
+}
+
+func TestScorePlacement(t *testing.T) {
+	v := newTestValidator()
+
+	got, err := v.ScorePlacement(boardWithCat(), []game.PlacedTile{place(6, 10, 'A'), place(7, 10, 'S')})
+	if err != nil {
+		t.Fatalf("ScorePlacement() error = %v", err)
+	}
+
+	wantWords := []game.WordScore{
+		{Word: "AS", WordMultiplier: 1, Score: 2},
+		{Word: "CATS", WordMultiplier: 1, Score: 6},
+	}
+	if !reflect.DeepEqual(got.Move.Breakdown.Words, wantWords) {
+		t.Errorf("Breakdown.Words = %v, want %v", got.Move.Breakdown.Words, wantWords)
+	}
+	if got.Move.Score != 8 || got.Move.Kind != game.MovePlace {
+		t.Errorf("Move = %d points of kind %d, want a placement for 8", got.Move.Score, got.Move.Kind)
+	}
+	if len(got.PhonyWords) != 0 {
+		t.Errorf("PhonyWords = %v, want none", got.PhonyWords)
+	}
+}
+
+func TestScorePlacementReportsPhonies(t *testing.T) {
+	v := newTestValidator()
+
+	got, err := v.ScorePlacement(boardWithCat(), []game.PlacedTile{place(7, 10, 'E')})
+	if err != nil {
+		t.Fatalf("ScorePlacement() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.PhonyWords, []string{"CATE"}) {
+		t.Errorf("PhonyWords = %v, want [CATE]", got.PhonyWords)
+	}
+	if got.Move.Score != 6 {
+		t.Errorf("Move.Score = %d, want 6", got.Move.Score)
+	}
+}
+
+func TestScorePlacementIgnoresTileValues(t *testing.T) {
+	v := newTestValidator()
+
+	// An S claiming to be worth 10 still scores 1
+	s := place(7, 10, 'S')
+	s.Tile.Value = 10
+	got, err := v.ScorePlacement(boardWithCat(), []game.PlacedTile{s})
+	if err != nil {
+		t.Fatalf("ScorePlacement() error = %v", err)
+	}
+	if got.Move.Score != 6 {
+		t.Errorf("Move.Score = %d, want 6", got.Move.Score)
+	}
+}
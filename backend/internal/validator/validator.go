@@ -0,0 +1,288 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+)
+
+// Reasons a placement can be rejected; match them with errors.Is
+var (
+	ErrNoTilesPlaced  = errors.New("move places no tiles")
+	ErrOffBoard       = errors.New("tile placed off the board")
+	ErrSquareOccupied = errors.New("square is already occupied")
+	ErrNotCollinear   = errors.New("tiles are not in a single row or column")
+	ErrGap            = errors.New("tiles leave a gap in the word")
+	ErrMissingCenter  = errors.New("first word must cover the centre square")
+	ErrNotConnected   = errors.New("word does not connect to existing tiles")
+	ErrNoWordFormed   = errors.New("move does not form a word of two or more letters")
+	ErrTileNotOnRack  = errors.New("tile is not on the player's rack")
+	ErrPhonyWord      = errors.New("word is not in the dictionary")
+)
+
+// PhonyWordError lists every word a move forms that is not in the dictionary
+type PhonyWordError struct {
+	Words []string
+}
+
+func (e *PhonyWordError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrPhonyWord, strings.Join(e.Words, ", "))
+}
+
+// Unwrap lets errors.Is(err, ErrPhonyWord) match
+func (e *PhonyWordError) Unwrap() error {
+	return ErrPhonyWord
+}
+
+// Validator checks moves against the rules and a dictionary
+type Validator struct {
+	gaddag gaddag.Lexicon
+	dist   *game.LetterDistribution
+}
+
+// New creates a validator that scores placements with the English tile values
+func New(g gaddag.Lexicon) *Validator {
+	return NewWithDistribution(g, game.EnglishDistribution())
+}
+
+// NewWithDistribution creates a validator that scores placements with the
+// tile values of the given distribution
+func NewWithDistribution(g gaddag.Lexicon, dist *game.LetterDistribution) *Validator {
+	return &Validator{gaddag: g, dist: dist}
+}
+
+// Validate checks that a move is a legal play for the given rack on the board.
+// It returns nil for a legal move, or an error wrapping one of the Err values
+// above describing the first problem found.
+func (v *Validator) Validate(b *board.Board, rack []game.Tile, move game.Move) error {
+	placed := move.TilesPlaced
+
+	if _, err := checkPlacement(b, placed); err != nil {
+		return err
+	}
+
+	if err := checkRack(rack, placed); err != nil {
+		return err
+	}
+
+	words := WordsFormed(b, placed)
+	if len(words) == 0 {
+		return ErrNoWordFormed
+	}
+
+	if phonies := v.phonyWords(words); len(phonies) > 0 {
+		return &PhonyWordError{Words: phonies}
+	}
+
+	return nil
+}
+
+// checkPlacement applies the rules that depend only on where tiles are
+// placed and returns the direction they lie in
+func checkPlacement(b *board.Board, placed []game.PlacedTile) (game.Direction, error) {
+	if len(placed) == 0 {
+		return game.Horizontal, ErrNoTilesPlaced
+	}
+
+	seen := make(map[game.Position]bool)
+	for _, p := range placed {
+		pos := p.Position
+		if !b.InBounds(pos.Row, pos.Col) {
+			return game.Horizontal, fmt.Errorf("%w: (%d,%d)", ErrOffBoard, pos.Row, pos.Col)
+		}
+		if !b.IsEmpty(pos.Row, pos.Col) || seen[pos] {
+			return game.Horizontal, fmt.Errorf("%w: (%d,%d)", ErrSquareOccupied, pos.Row, pos.Col)
+		}
+		seen[pos] = true
+	}
+
+	dir, ok := placementDirection(placed)
+	if !ok {
+		return dir, ErrNotCollinear
+	}
+
+	if hasGap(b, placed, dir) {
+		return dir, ErrGap
+	}
+
+	if b.IsCompletelyEmpty() {
+		if !seen[b.Center()] {
+			return dir, ErrMissingCenter
+		}
+	} else if !touchesExisting(b, placed) {
+		return dir, ErrNotConnected
+	}
+
+	return dir, nil
+}
+
+// phonyWords returns the words that are not in the dictionary
+func (v *Validator) phonyWords(words []string) []string {
+	var phonies []string
+	for _, word := range words {
+		if !v.gaddag.Contains(word) {
+			phonies = append(phonies, word)
+		}
+	}
+	return phonies
+}
+
+// WordsFormed returns every word of two or more letters created by placing
+// tiles on the board: the main word first, then any cross words.
+// The tiles must already be known to be collinear and gap-free.
+func WordsFormed(b *board.Board, placed []game.PlacedTile) []string {
+	if len(placed) == 0 {
+		return nil
+	}
+
+	at := tileLookup(b, placed)
+	dir, _ := placementDirection(placed)
+
+	var words []string
+
+	main := readWord(at, placed[0].Position, dir)
+	if len([]rune(main)) > 1 {
+		words = append(words, main)
+	}
+
+	// Every placed tile can form a perpendicular word
+	crossDir := game.Vertical
+	if dir == game.Vertical {
+		crossDir = game.Horizontal
+	}
+	for _, p := range placed {
+		cross := readWord(at, p.Position, crossDir)
+		if len([]rune(cross)) > 1 {
+			words = append(words, cross)
+		}
+	}
+
+	return words
+}
+
+// placementDirection returns the line the tiles lie on. A single tile is
+// treated as horizontal; WordsFormed still finds its vertical word as a cross word.
+func placementDirection(placed []game.PlacedTile) (game.Direction, bool) {
+	if len(placed) == 1 {
+		return game.Horizontal, true
+	}
+
+	sameRow, sameCol := true, true
+	for _, p := range placed[1:] {
+		if p.Position.Row != placed[0].Position.Row {
+			sameRow = false
+		}
+		if p.Position.Col != placed[0].Position.Col {
+			sameCol = false
+		}
+	}
+
+	switch {
+	case sameRow:
+		return game.Horizontal, true
+	case sameCol:
+		return game.Vertical, true
+	default:
+		return game.Horizontal, false
+	}
+}
+
+// hasGap reports whether any square between the first and last placed tile
+// is left empty
+func hasGap(b *board.Board, placed []game.PlacedTile, dir game.Direction) bool {
+	first, last := placed[0].Position, placed[0].Position
+	for _, p := range placed[1:] {
+		if p.Position.Row < first.Row || p.Position.Col < first.Col {
+			first = p.Position
+		}
+		if p.Position.Row > last.Row || p.Position.Col > last.Col {
+			last = p.Position
+		}
+	}
+
+	at := tileLookup(b, placed)
+	for pos := first; pos != last; pos = step(pos, dir, 1) {
+		if at(pos) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// touchesExisting reports whether any placed tile is next to a tile already on the board
+func touchesExisting(b *board.Board, placed []game.PlacedTile) bool {
+	for _, p := range placed {
+		row, col := p.Position.Row, p.Position.Col
+		if !b.IsEmpty(row-1, col) || !b.IsEmpty(row+1, col) ||
+			!b.IsEmpty(row, col-1) || !b.IsEmpty(row, col+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRack ensures every placed tile comes from the rack; a placed blank
+// uses up any blank on the rack
+func checkRack(rack []game.Tile, placed []game.PlacedTile) error {
+	available := make(map[rune]int)
+	for _, tile := range rack {
+		available[rackLetter(tile)]++
+	}
+
+	for _, p := range placed {
+		letter := rackLetter(p.Tile)
+		if available[letter] == 0 {
+			return fmt.Errorf("%w: %c", ErrTileNotOnRack, letter)
+		}
+		available[letter]--
+	}
+	return nil
+}
+
+// rackLetter returns the letter a tile occupies on a rack, with blanks as '?'
+func rackLetter(tile game.Tile) rune {
+	if tile.IsBlank {
+		return game.BlankLetter
+	}
+	return tile.Letter
+}
+
+// tileLookup returns a function giving the tile at a position with the
+// placed tiles laid over the board
+func tileLookup(b *board.Board, placed []game.PlacedTile) func(game.Position) *game.Tile {
+	overlay := make(map[game.Position]*game.Tile, len(placed))
+	for i := range placed {
+		overlay[placed[i].Position] = &placed[i].Tile
+	}
+
+	return func(pos game.Position) *game.Tile {
+		if tile, ok := overlay[pos]; ok {
+			return tile
+		}
+		return b.GetTile(pos.Row, pos.Col)
+	}
+}
+
+// readWord reads the full run of tiles through pos in the given direction
+func readWord(at func(game.Position) *game.Tile, pos game.Position, dir game.Direction) string {
+	start := pos
+	for at(step(start, dir, -1)) != nil {
+		start = step(start, dir, -1)
+	}
+
+	var word strings.Builder
+	for p := start; at(p) != nil; p = step(p, dir, 1) {
+		word.WriteRune(at(p).Letter)
+	}
+	return word.String()
+}
+
+func step(pos game.Position, dir game.Direction, n int) game.Position {
+	if dir == game.Horizontal {
+		return game.Position{Row: pos.Row, Col: pos.Col + n}
+	}
+	return game.Position{Row: pos.Row + n, Col: pos.Col}
+}
@@ -0,0 +1,203 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"tiletactics/backend/internal/board"
+	"tiletactics/backend/internal/gaddag"
+	"tiletactics/backend/internal/game"
+)
+
+func newTestValidator() *Validator {
+	g := gaddag.New()
+	for _, word := range []string{"CAT", "CATS", "AT", "AS", "TA", "SCAT"} {
+		g.Add(word)
+	}
+	return New(g)
+}
+
+// boardWithCat returns a board with CAT across from (7,7)
+func boardWithCat() *board.Board {
+	b := board.New()
+	b.SetTile(7, 7, &game.Tile{Letter: 'C', Value: 3})
+	b.SetTile(7, 8, &game.Tile{Letter: 'A', Value: 1})
+	b.SetTile(7, 9, &game.Tile{Letter: 'T', Value: 1})
+	return b
+}
+
+func place(row, col int, letter rune) game.PlacedTile {
+	return game.PlacedTile{
+		Position: game.Position{Row: row, Col: col},
+		Tile:     game.Tile{Letter: letter, Value: game.TileValues[letter]},
+	}
+}
+
+func rackOf(letters string) []game.Tile {
+	rack := make([]game.Tile, 0, len(letters))
+	for _, letter := range letters {
+		if letter == game.BlankLetter {
+			rack = append(rack, game.Tile{Letter: game.BlankLetter, IsBlank: true})
+		} else {
+			rack = append(rack, game.Tile{Letter: letter, Value: game.TileValues[letter]})
+		}
+	}
+	return rack
+}
+
+func TestValidate(t *testing.T) {
+	v := newTestValidator()
+
+	tests := []struct {
+		name   string
+		board  *board.Board
+		rack   string
+		placed []game.PlacedTile
+		want   error
+	}{
+		{
+			name:   "Legal opening move",
+			board:  board.New(),
+			rack:   "CATXYZQ",
+			placed: []game.PlacedTile{place(7, 7, 'C'), place(7, 8, 'A'), place(7, 9, 'T')},
+			want:   nil,
+		},
+		{
+			name:   "Legal hook",
+			board:  boardWithCat(),
+			rack:   "S",
+			placed: []game.PlacedTile{place(7, 10, 'S')},
+			want:   nil,
+		},
+		{
+			name:   "No tiles",
+			board:  board.New(),
+			rack:   "CAT",
+			placed: nil,
+			want:   ErrNoTilesPlaced,
+		},
+		{
+			name:   "Off the board",
+			board:  boardWithCat(),
+			rack:   "S",
+			placed: []game.PlacedTile{place(7, 15, 'S')},
+			want:   ErrOffBoard,
+		},
+		{
+			name:   "Overlaps existing tile",
+			board:  boardWithCat(),
+			rack:   "S",
+			placed: []game.PlacedTile{place(7, 8, 'S')},
+			want:   ErrSquareOccupied,
+		},
+		{
+			name:   "Not collinear",
+			board:  board.New(),
+			rack:   "CAT",
+			placed: []game.PlacedTile{place(7, 7, 'C'), place(8, 8, 'A'), place(7, 9, 'T')},
+			want:   ErrNotCollinear,
+		},
+		{
+			name:   "Gap in word",
+			board:  board.New(),
+			rack:   "CAT",
+			placed: []game.PlacedTile{place(7, 6, 'C'), place(7, 7, 'A'), place(7, 9, 'T')},
+			want:   ErrGap,
+		},
+		{
+			name:   "First move misses centre",
+			board:  board.New(),
+			rack:   "CAT",
+			placed: []game.PlacedTile{place(0, 0, 'C'), place(0, 1, 'A'), place(0, 2, 'T')},
+			want:   ErrMissingCenter,
+		},
+		{
+			name:   "Disconnected",
+			board:  boardWithCat(),
+			rack:   "AT",
+			placed: []game.PlacedTile{place(0, 0, 'A'), place(0, 1, 'T')},
+			want:   ErrNotConnected,
+		},
+		{
+			name:   "Single tile opening",
+			board:  board.New(),
+			rack:   "A",
+			placed: []game.PlacedTile{place(7, 7, 'A')},
+			want:   ErrNoWordFormed,
+		},
+		{
+			name:   "Tile not on rack",
+			board:  boardWithCat(),
+			rack:   "E",
+			placed: []game.PlacedTile{place(7, 10, 'S')},
+			want:   ErrTileNotOnRack,
+		},
+		{
+			name:   "Phony word",
+			board:  boardWithCat(),
+			rack:   "E",
+			placed: []game.PlacedTile{place(7, 10, 'E')},
+			want:   ErrPhonyWord,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			move := game.Move{TilesPlaced: tt.placed}
+			err := v.Validate(tt.board, rackOf(tt.rack), move)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBlankUsesRackBlank(t *testing.T) {
+	v := newTestValidator()
+
+	blankS := place(7, 10, 'S')
+	blankS.Tile.IsBlank = true
+	blankS.Tile.Value = 0
+
+	if err := v.Validate(boardWithCat(), rackOf("?"), game.Move{TilesPlaced: []game.PlacedTile{blankS}}); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	if err := v.Validate(boardWithCat(), rackOf("S"), game.Move{TilesPlaced: []game.PlacedTile{blankS}}); !errors.Is(err, ErrTileNotOnRack) {
+		t.Errorf("Validate() error = %v, want ErrTileNotOnRack", err)
+	}
+}
+
+func TestPhonyWordErrorListsWords(t *testing.T) {
+	v := newTestValidator()
+
+	// XE under CA forms XE across plus CX and AE down
+	err := v.Validate(boardWithCat(), rackOf("XE"), game.Move{
+		TilesPlaced: []game.PlacedTile{place(8, 7, 'X'), place(8, 8, 'E')},
+	})
+
+	var phony *PhonyWordError
+	if !errors.As(err, &phony) {
+		t.Fatalf("Validate() error = %v, want *PhonyWordError", err)
+	}
+	want := []string{"XE", "CX", "AE"}
+	if !reflect.DeepEqual(phony.Words, want) {
+		t.Errorf("PhonyWordError.Words = %v, want %v", phony.Words, want)
+	}
+}
+
+func TestWordsFormed(t *testing.T) {
+	b := boardWithCat()
+
+	// S hooked on CAT and extended down into AS
+	got := WordsFormed(b, []game.PlacedTile{place(6, 10, 'A'), place(7, 10, 'S')})
+	want := []string{"AS", "CATS"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WordsFormed() = %v, want %v", got, want)
+	}
+}
@@ -375,7 +375,11 @@ export default function BoardAnalysis() {
                       <div className="rank-indicator"></div>
                     </div>
                     <div className="move-details">
-                      <span className="move-word">{move.word}</span>
+                      <span className="move-word">
+                        {move.kind === 'exchange'
+                          ? `Exchange ${(move.exchanged ?? []).map(t => t.letter).join('')}`
+                          : move.kind === 'pass' ? 'Pass' : move.word}
+                      </span>
                       <div className="move-score">
                         <span className="score-value">{move.score}</span>
                         <span className="score-label">pts</span>
@@ -3,6 +3,7 @@ declare global {
     Go: any;
     analyzePosition: (request: string) => string;
     validateWords: (request: string) => string;
+    scorePlacement: (request: string) => string;
     __wasmCleanup?: () => void;
   }
 }
@@ -123,23 +124,53 @@ export interface TileData {
   isBlank: boolean;
 }
 
+export interface BoardLayout {
+  name: string;
+  size: number;
+  start: { row: number; col: number };
+  rows: string[];
+}
+
 export interface AnalysisRequest {
   board: (TileData | null)[][];
   rack: TileData[];
   remainingTiles: Record<string, number>;
   dictionary: string;
+  layout?: 'standard' | 'super';
+  customLayout?: BoardLayout;
+  distribution?: string; // "english" (default) or a file served from /distributions/, e.g. "spanish"
+  bagSize?: number; // Tiles in the bag; when set, exchanges and passing are considered too
+  cgp?: string; // A position string; replaces board, rack and remainingTiles when set
 }
 
 export interface MoveResult {
+  kind: 'place' | 'exchange' | 'pass';
+  notation: string; // Tournament notation, e.g. "8G Q(U)IXOTIc"
   word: string;
   position: { row: number; col: number };
-  direction: 'H' | 'V';
+  direction: 'H' | 'V' | ''; // Empty for exchanges and passes
   score: number;
   tilesPlaced: Array<{
     position: { row: number; col: number };
     tile: TileData;
   }>;
+  exchanged?: TileData[];
   leave: TileData[];
+  breakdown?: ScoreBreakdown;
+}
+
+export interface ScoreBreakdown {
+  words: Array<{
+    word: string;
+    premiums: Array<{
+      position: { row: number; col: number };
+      type: string;
+      multiplier: number;
+    }>;
+    wordMultiplier: number;
+    score: number;
+  }>;
+  bingo: number;
 }
 
 export interface AnalysisResponse {
@@ -151,6 +182,7 @@ export interface AnalysisResponse {
 export interface ValidationRequest {
   words: string[];
   dictionary: string;
+  distribution?: string;
 }
 
 export interface WordValidation {
@@ -165,6 +197,36 @@ export interface ValidationResponse {
   error?: string;
 }
 
+// Placement scoring types
+export interface PlacementRequest {
+  board: (TileData | null)[][];
+  tiles: Array<{
+    position: { row: number; col: number };
+    tile: TileData;
+  }>;
+  move?: string; // A move such as "8G Q(U)IXOTIc", used instead of tiles
+  dictionary: string;
+  layout?: 'standard' | 'super';
+  customLayout?: BoardLayout;
+  distribution?: string;
+}
+
+export interface WordScore {
+  word: string;
+  score: number;
+  isValid: boolean;
+}
+
+export interface PlacementResponse {
+  move?: MoveResult;
+  words: WordScore[];
+  bingo: number;
+  allValid: boolean;
+  invalidWords?: string[];
+  reason?: string;
+  error?: string;
+}
+
 export async function analyzeBoard(request: AnalysisRequest): Promise<AnalysisResponse> {
   // Ensure WASM is loaded
   await loadWasm();
@@ -287,6 +349,19 @@ export async function validateWords(words: string[], dictionary: string): Promis
   }
 }
 
+export async function scorePlacement(request: PlacementRequest): Promise<PlacementResponse> {
+  // Ensure WASM is loaded
+  await loadWasm();
+
+  // Convert board nulls to empty tiles for JSON
+  const boardForWasm = request.board.map(row =>
+    row.map(tile => tile || { letter: '', value: 0, isBlank: false })
+  );
+
+  const responseStr = window.scorePlacement(JSON.stringify({ ...request, board: boardForWasm }));
+  return JSON.parse(responseStr) as PlacementResponse;
+}
+
 // Clean up on page unload
 if (typeof window !== 'undefined') {
   window.addEventListener('beforeunload', () => {